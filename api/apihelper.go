@@ -23,6 +23,7 @@ import (
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
 	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/http"
 	cjson "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/json"
+	ctime "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
 )
 
 const (
@@ -37,6 +38,31 @@ type APIHelper struct {
 	Endpoint *APIEndpoint
 	Client   *CFClient
 	Logger   trace.Printer
+	// TimeFormatter formats the timestamps of metrics and scaling events
+	TimeFormatter ctime.Formatter
+	// HealthChecked skips the health check before each request, for helpers
+	// of apps sharing an endpoint that was checked already
+	HealthChecked bool
+}
+
+// InvalidPolicyError is returned when the AutoScaler API rejects a policy,
+// Reason is the error from the response.
+type InvalidPolicyError struct {
+	Reason string
+}
+
+func (e *InvalidPolicyError) Error() string {
+	return fmt.Sprintf(ui.InvalidPolicy, e.Reason)
+}
+
+// PolicyNotFoundError is returned by GetPolicy when no policy is attached to
+// the app.
+type PolicyNotFoundError struct {
+	AppName string
+}
+
+func (e *PolicyNotFoundError) Error() string {
+	return fmt.Sprintf(ui.PolicyNotFound, e.AppName)
 }
 
 func NewAPIHelper(endpoint *APIEndpoint, cfclient *CFClient, traceEnabled string) *APIHelper {
@@ -48,14 +74,14 @@ func NewAPIHelper(endpoint *APIEndpoint, cfclient *CFClient, traceEnabled string
 	}
 }
 
-func newHTTPClient(skipSSLValidation bool, logger trace.Printer) *http.Client {
+func newHTTPClient(skipSSLValidation bool, rootCAs *x509.CertPool, logger trace.Printer) *http.Client {
 	return &http.Client{
-		Transport: makeTransport(skipSSLValidation, logger),
+		Transport: makeTransport(skipSSLValidation, rootCAs, logger),
 		Timeout:   30 * time.Second,
 	}
 }
 
-func makeTransport(skipSSLValidation bool, logger trace.Printer) http.RoundTripper {
+func makeTransport(skipSSLValidation bool, rootCAs *x509.CertPool, logger trace.Printer) http.RoundTripper {
 	return NewTraceLoggingTransport(&http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		Dial: (&net.Dialer{
@@ -66,13 +92,23 @@ func makeTransport(skipSSLValidation bool, logger trace.Printer) http.RoundTripp
 		DisableCompression:  true,
 		DisableKeepAlives:   true,
 		// #nosec G402
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipSSLValidation},
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipSSLValidation, RootCAs: rootCAs},
 	}, logger)
 }
 
 func (helper *APIHelper) DoRequest(req *http.Request) (*http.Response, error) {
 
-	client := newHTTPClient(helper.Endpoint.SkipSSLValidation || helper.Client.IsSSLDisabled, helper.Logger)
+	skipSSLValidation := helper.Endpoint.SkipSSLValidation || helper.Client.IsSSLDisabled
+	// the CA certificates are only loaded when they are verified
+	var rootCAs *x509.CertPool
+	if req.URL.Scheme == "https" && !skipSSLValidation {
+		var err error
+		rootCAs, err = loadCertPool(helper.Endpoint.CACert)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client := newHTTPClient(skipSSLValidation, rootCAs, helper.Logger)
 	resp, err := client.Do(req)
 	if err != nil {
 		var innerErr error
@@ -165,6 +201,9 @@ func parseErrResponse(raw []byte) string {
 }
 
 func (helper *APIHelper) CheckHealth() error {
+	if helper.HealthChecked {
+		return nil
+	}
 	baseURL := helper.Endpoint.URL
 	requestURL := fmt.Sprintf("%s%s", baseURL, HealthPath)
 	req, err := http.NewRequest("GET", requestURL, nil)
@@ -192,6 +231,26 @@ func (helper *APIHelper) CheckHealth() error {
 
 func (helper *APIHelper) GetPolicy() ([]byte, error) {
 
+	policy, err := helper.GetScalingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, &PolicyNotFoundError{AppName: helper.Client.AppName}
+	}
+
+	prettyPolicy, err := cjson.MarshalWithoutHTMLEscape(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return prettyPolicy, nil
+
+}
+
+// GetScalingPolicy returns the policy attached to the app, or nil if there is none.
+func (helper *APIHelper) GetScalingPolicy() (*models.ScalingPolicy, error) {
+
 	err := helper.CheckHealth()
 	if err != nil {
 		return nil, err
@@ -209,13 +268,14 @@ func (helper *APIHelper) GetPolicy() ([]byte, error) {
 	defer resp.Body.Close()
 
 	raw, err := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		var errorMsg string
 		switch resp.StatusCode {
 		case 401:
 			errorMsg = fmt.Sprintf(ui.Unauthorized, baseURL)
-		case 404:
-			errorMsg = fmt.Sprintf(ui.PolicyNotFound, helper.Client.AppName)
 		default:
 			errorMsg = parseErrResponse(raw)
 		}
@@ -228,13 +288,7 @@ func (helper *APIHelper) GetPolicy() ([]byte, error) {
 		return nil, err
 	}
 
-	prettyPolicy, err := cjson.MarshalWithoutHTMLEscape(policy)
-	if err != nil {
-		return nil, err
-	}
-
-	return prettyPolicy, nil
-
+	return &policy, nil
 }
 
 func (helper *APIHelper) CreatePolicy(data interface{}) error {
@@ -275,7 +329,7 @@ func (helper *APIHelper) CreatePolicy(data interface{}) error {
 		case 401:
 			errorMsg = fmt.Sprintf(ui.Unauthorized, baseURL)
 		case 400:
-			errorMsg = fmt.Sprintf(ui.InvalidPolicy, parseErrResponse(raw))
+			return &InvalidPolicyError{Reason: parseErrResponse(raw)}
 		default:
 			errorMsg = parseErrResponse(raw)
 		}
@@ -323,6 +377,21 @@ func (helper *APIHelper) DeletePolicy() error {
 
 func (helper *APIHelper) GetAggregatedMetrics(metricName string, startTime, endTime int64, asc bool, page uint64) (bool, [][]string, error) {
 
+	next, metrics, err := helper.GetAggregatedMetricRecords(metricName, startTime, endTime, asc, page)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var data [][]string
+	for _, entry := range metrics {
+		data = append(data, helper.MetricRow(entry))
+	}
+	return next, data, nil
+
+}
+
+func (helper *APIHelper) GetAggregatedMetricRecords(metricName string, startTime, endTime int64, asc bool, page uint64) (bool, []*models.AppAggregatedMetric, error) {
+
 	if page <= 1 {
 		err := helper.CheckHealth()
 		if err != nil {
@@ -376,21 +445,66 @@ func (helper *APIHelper) GetAggregatedMetrics(metricName string, startTime, endT
 		return false, nil, err
 	}
 
-	var data [][]string
-	for _, entry := range metrics.Metrics {
-		data = append(data, []string{entry.Name, entry.Value + entry.Unit, time.Unix(0, entry.Timestamp).Format(time.RFC3339)})
-	}
-
 	if metrics.Page < metrics.TotalPages {
-		return true, data, nil
+		return true, metrics.Metrics, nil
 	} else {
-		return false, data, nil
+		return false, metrics.Metrics, nil
 	}
 
 }
 
 func (helper *APIHelper) GetHistory(startTime, endTime int64, asc bool, page uint64) (bool, [][]string, error) {
 
+	next, histories, err := helper.GetHistoryRecords(startTime, endTime, asc, page)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var data [][]string
+	for _, entry := range histories {
+		data = append(data, helper.HistoryRow(entry))
+	}
+	return next, data, nil
+
+}
+
+// MetricRow formats an aggregated metric for the metrics table, the columns
+// are metric name, value with unit and timestamp.
+func (helper *APIHelper) MetricRow(entry *models.AppAggregatedMetric) []string {
+	return []string{entry.Name, entry.Value + entry.Unit, helper.TimeFormatter.Format(entry.Timestamp)}
+}
+
+// HistoryRow formats a scaling event for the history table, the columns are
+// scaling type, status, instance changes, time, action and error.
+func (helper *APIHelper) HistoryRow(entry *models.AppScalingHistory) []string {
+	scalingType := "dynamic"
+	if entry.ScalingType == models.ScalingTypeScheduled {
+		scalingType = "scheduled"
+	}
+	status := "succeeded"
+	instanceChange := strconv.Itoa(entry.OldInstances) + "->" + strconv.Itoa(entry.NewInstances)
+	if entry.Status == models.ScalingStatusFailed {
+		status = "failed"
+		instanceChange = ""
+	}
+
+	reason := entry.Reason
+	var adjustment = entry.NewInstances - entry.OldInstances
+	if entry.Message != "" {
+		if adjustment >= 0 {
+			reason = fmt.Sprintf("+%d instance(s) because %s", adjustment, entry.Message)
+		} else {
+			reason = fmt.Sprintf("%d instance(s) because %s", adjustment, entry.Message)
+		}
+	}
+	return []string{scalingType, status, instanceChange,
+		helper.TimeFormatter.Format(entry.Timestamp),
+		reason, entry.Error,
+	}
+}
+
+func (helper *APIHelper) GetHistoryRecords(startTime, endTime int64, asc bool, page uint64) (bool, []*models.AppScalingHistory, error) {
+
 	if page <= 1 {
 		err := helper.CheckHealth()
 		if err != nil {
@@ -442,37 +556,101 @@ func (helper *APIHelper) GetHistory(startTime, endTime int64, asc bool, page uin
 		return false, nil, err
 	}
 
-	var data [][]string
-	for _, entry := range history.Histories {
-		scalingType := "dynamic"
-		if entry.ScalingType == 1 {
-			scalingType = "scheduled"
-		}
-		status := "succeeded"
-		instanceChange := strconv.Itoa(entry.OldInstances) + "->" + strconv.Itoa(entry.NewInstances)
-		if entry.Status == 1 {
-			status = "failed"
-			instanceChange = ""
+	if history.Page < history.TotalPages {
+		return true, history.Histories, nil
+	} else {
+		return false, history.Histories, nil
+	}
+
+}
+
+func (helper *APIHelper) CreateCredential(data interface{}) ([]byte, error) {
+
+	err := helper.CheckHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := helper.Endpoint.URL
+	requestURL := fmt.Sprintf("%s%s", baseURL, strings.Replace(CredentialPath, "{appId}", helper.Client.AppId, -1))
+
+	var body io.Reader
+	if data != nil {
+		jsonByte, e := json.Marshal(data)
+		if e != nil {
+			return nil, fmt.Errorf(ui.InvalidCredential, e)
 		}
+		body = bytes.NewBuffer(jsonByte)
+	}
 
-		var adjustment = entry.NewInstances - entry.OldInstances
-		if entry.Message != "" {
-			if adjustment >= 0 {
-				entry.Reason = fmt.Sprintf("+%d instance(s) because %s", adjustment, entry.Message)
-			} else {
-				entry.Reason = fmt.Sprintf("%d instance(s) because %s", adjustment, entry.Message)
-			}
+	req, err := http.NewRequest("PUT", requestURL, body)
+	req.Header.Add("Authorization", helper.Client.AuthToken)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := helper.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errorMsg string
+		switch resp.StatusCode {
+		case 401:
+			errorMsg = fmt.Sprintf(ui.Unauthorized, baseURL)
+		case 400:
+			errorMsg = fmt.Sprintf(ui.InvalidCredential, parseErrResponse(raw))
+		default:
+			errorMsg = parseErrResponse(raw)
 		}
-		data = append(data, []string{scalingType, status, instanceChange,
-			time.Unix(0, entry.Timestamp).Format(time.RFC3339),
-			entry.Reason, entry.Error,
-		})
+		return nil, errors.New(errorMsg)
 	}
 
-	if history.Page < history.TotalPages {
-		return true, data, nil
-	} else {
-		return false, data, nil
+	var credential models.CredentialResponse
+	err = json.Unmarshal(raw, &credential)
+	if err != nil {
+		return nil, err
+	}
+
+	prettyCredential, err := cjson.MarshalWithoutHTMLEscape(credential)
+	if err != nil {
+		return nil, err
 	}
 
+	return prettyCredential, nil
+}
+
+func (helper *APIHelper) DeleteCredential() error {
+
+	err := helper.CheckHealth()
+	if err != nil {
+		return err
+	}
+
+	baseURL := helper.Endpoint.URL
+	requestURL := fmt.Sprintf("%s%s", baseURL, strings.Replace(CredentialPath, "{appId}", helper.Client.AppId, -1))
+
+	req, err := http.NewRequest("DELETE", requestURL, nil)
+	req.Header.Add("Authorization", helper.Client.AuthToken)
+
+	resp, err := helper.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		var errorMsg string
+		switch resp.StatusCode {
+		case 401:
+			errorMsg = fmt.Sprintf(ui.Unauthorized, baseURL)
+		default:
+			errorMsg = parseErrResponse(raw)
+		}
+		return errors.New(errorMsg)
+	}
+
+	return nil
 }
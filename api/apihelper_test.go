@@ -2,9 +2,11 @@ package api_test
 
 import (
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -61,11 +63,11 @@ var _ = Describe("API Helper Test", func() {
 			Configuration: &Configuration{
 				CustomMetrics: struct {
 					MetricSubmissionStrategy struct {
-						AllowFrom string `json:"allow_from"`
-					} `json:"metric_submission_strategy"`
+						AllowFrom string `json:"allow_from" yaml:"allow_from"`
+					} `json:"metric_submission_strategy" yaml:"metric_submission_strategy"`
 				}{
 					MetricSubmissionStrategy: struct {
-						AllowFrom string `json:"allow_from"`
+						AllowFrom string `json:"allow_from" yaml:"allow_from"`
 					}{
 						AllowFrom: "bound_app",
 					},
@@ -206,6 +208,68 @@ var _ = Describe("API Helper Test", func() {
 
 		})
 
+		Context("Connecting to a TLS server with a private CA", func() {
+			var apiTLSServer *ghttp.Server
+			var apiTLSHelper *APIHelper
+			var caCert string
+
+			BeforeEach(func() {
+				apiTLSServer = ghttp.NewTLSServer()
+				apiTLSServer.RouteToHandler("GET", "/health",
+					ghttp.RespondWith(http.StatusOK, ""),
+				)
+				caCert = filepath.Join(GinkgoT().TempDir(), "ca.pem")
+				certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: apiTLSServer.HTTPTestServer.Certificate().Raw})
+				Expect(os.WriteFile(caCert, certPEM, 0600)).To(Succeed())
+
+				apiTLSHelper = NewAPIHelper(
+					&APIEndpoint{
+						URL: apiTLSServer.URL(),
+					},
+					&CFClient{
+						CCAPIEndpoint: "fakeCCAPI",
+						AuthToken:     fakeAccessToken,
+						AppId:         fakeAppId,
+						AppName:       "fakeAppName",
+					},
+					"false",
+				)
+			})
+
+			AfterEach(func() {
+				apiTLSServer.Close()
+			})
+
+			It("Succeed to check health with the CA certificate", func() {
+				apiTLSHelper.Endpoint.CACert = caCert
+				Expect(apiTLSHelper.CheckHealth()).To(Succeed())
+			})
+
+			It("Succeed to check health with the CA certificate in SSL_CERT_FILE", func() {
+				GinkgoT().Setenv("SSL_CERT_FILE", caCert)
+				Expect(apiTLSHelper.CheckHealth()).To(Succeed())
+			})
+
+			It("Succeed to check health with the CA certificate in SSL_CERT_DIR", func() {
+				GinkgoT().Setenv("SSL_CERT_DIR", filepath.Dir(caCert))
+				Expect(apiTLSHelper.CheckHealth()).To(Succeed())
+			})
+
+			It("Fail with a file without certificates", func() {
+				Expect(os.WriteFile(caCert, []byte("not a certificate"), 0600)).To(Succeed())
+				apiTLSHelper.Endpoint.CACert = caCert
+				err = apiTLSHelper.CheckHealth()
+				Expect(err).Should(MatchError(fmt.Sprintf(ui.InvalidCACert, caCert, "no PEM encoded certificate found")))
+			})
+
+			It("Fail with a missing file", func() {
+				apiTLSHelper.Endpoint.CACert = caCert + ".missing"
+				err = apiTLSHelper.CheckHealth()
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(HavePrefix(fmt.Sprintf("Failed to load CA certificate %s.missing:", caCert)))
+			})
+		})
+
 	})
 
 	Context("When API Server is valid", func() {
@@ -272,6 +336,31 @@ var _ = Describe("API Helper Test", func() {
 				})
 			})
 
+			Context("Get typed policy", func() {
+				It("succeed with the attached policy", func() {
+					apiServer.RouteToHandler("GET", urlpath,
+						ghttp.CombineHandlers(
+							ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+							ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+						),
+					)
+
+					policy, err := apihelper.GetScalingPolicy()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(*policy).To(Equal(fakePolicy))
+				})
+
+				It("succeed without a policy when none is attached", func() {
+					apiServer.RouteToHandler("GET", urlpath,
+						ghttp.RespondWith(http.StatusNotFound, ""),
+					)
+
+					policy, err := apihelper.GetScalingPolicy()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(policy).To(BeNil())
+				})
+			})
+
 			Context("Unauthorized Access", func() {
 				BeforeEach(func() {
 					apiServer.RouteToHandler("GET", urlpath,
@@ -297,6 +386,7 @@ var _ = Describe("API Helper Test", func() {
 					_, err = apihelper.GetPolicy()
 					Expect(err).Should(HaveOccurred())
 					Expect(err).Should(MatchError(fmt.Sprintf(ui.PolicyNotFound, apihelper.Client.AppName)))
+					Expect(err).Should(BeAssignableToTypeOf(&PolicyNotFoundError{}))
 				})
 			})
 			Context("Default error handling", func() {
@@ -390,6 +480,7 @@ var _ = Describe("API Helper Test", func() {
 						err = apihelper.CreatePolicy(fakePolicy)
 						Expect(err).Should(HaveOccurred())
 						Expect(err).Should(MatchError(fmt.Sprintf(ui.InvalidPolicy, "\n"+"instance_min_count 10 is higher or equal to instance_max_count 2 in policy_json")))
+						Expect(err).Should(BeAssignableToTypeOf(&InvalidPolicyError{}))
 					})
 				})
 
@@ -515,6 +606,142 @@ var _ = Describe("API Helper Test", func() {
 
 		})
 
+		Context("Create Credential", func() {
+			var urlpath = "/v1/apps/" + fakeAppId + "/credential"
+
+			Context("Succeed with a random credential", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("PUT", urlpath,
+						ghttp.CombineHandlers(
+							ghttp.RespondWith(http.StatusOK, `{"app_id":"fakeAppId","username":"random-user","password":"random-pass","url":"https://autoscalermetrics.example.com"}`),
+							ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+						),
+					)
+				})
+
+				It("succeed", func() {
+					response, err := apihelper.CreateCredential(nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(MatchJSON(`{"app_id":"fakeAppId","username":"random-user","password":"random-pass","url":"https://autoscalermetrics.example.com"}`))
+				})
+			})
+
+			Context("Succeed with a user-defined credential", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("PUT", urlpath,
+						ghttp.CombineHandlers(
+							ghttp.VerifyJSON(`{"username":"user","password":"pass"}`),
+							ghttp.RespondWith(http.StatusOK, `{"app_id":"fakeAppId","username":"user","password":"pass","url":"https://autoscalermetrics.example.com"}`),
+							ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+						),
+					)
+				})
+
+				It("succeed", func() {
+					response, err := apihelper.CreateCredential(Credential{Username: "user", Password: "pass"})
+					Expect(err).NotTo(HaveOccurred())
+
+					var actualCredential CredentialResponse
+					_ = json.Unmarshal(response, &actualCredential)
+					Expect(actualCredential.AppId).To(Equal(fakeAppId))
+					Expect(actualCredential.Username).To(Equal("user"))
+					Expect(actualCredential.Password).To(Equal("pass"))
+				})
+			})
+
+			Context("Unauthorized Access", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("PUT", urlpath,
+						ghttp.RespondWith(http.StatusUnauthorized, ""),
+					)
+				})
+
+				It("Fail with 401 error", func() {
+					_, err = apihelper.CreateCredential(nil)
+					Expect(err).Should(HaveOccurred())
+					Expect(err).Should(MatchError(fmt.Sprintf(ui.Unauthorized, apihelper.Endpoint.URL)))
+				})
+			})
+
+			Context("Invalid credential", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("PUT", urlpath,
+						ghttp.RespondWith(http.StatusBadRequest, `[{"context":"(root).password","description":"password is required"}]`),
+					)
+				})
+
+				It("Fail with 400 error", func() {
+					_, err = apihelper.CreateCredential(Credential{Username: "user"})
+					Expect(err).Should(HaveOccurred())
+					Expect(err).Should(MatchError(fmt.Sprintf(ui.InvalidCredential, "\n(root).password: password is required")))
+				})
+			})
+
+			Context("Default error handling", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("PUT", urlpath,
+						ghttp.RespondWith(http.StatusInternalServerError, `{"success":false,"error":{"message":"Internal error","statusCode":500},"result":null}`),
+					)
+				})
+
+				It("Fail with 500 error", func() {
+					_, err = apihelper.CreateCredential(nil)
+					Expect(err).Should(HaveOccurred())
+					Expect(err).Should(MatchError("Internal error"))
+				})
+			})
+
+		})
+
+		Context("Delete Credential", func() {
+			var urlpath = "/v1/apps/" + fakeAppId + "/credential"
+
+			Context("Succeed with valid auth token", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("DELETE", urlpath,
+						ghttp.CombineHandlers(
+							ghttp.RespondWith(http.StatusOK, ""),
+							ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+						),
+					)
+				})
+
+				It("succeed", func() {
+					err = apihelper.DeleteCredential()
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("Unauthorized Access", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("DELETE", urlpath,
+						ghttp.RespondWith(http.StatusUnauthorized, ""),
+					)
+				})
+
+				It("Fail with 401 error", func() {
+					err = apihelper.DeleteCredential()
+					Expect(err).Should(HaveOccurred())
+					Expect(err).Should(MatchError(fmt.Sprintf(ui.Unauthorized, apihelper.Endpoint.URL)))
+				})
+			})
+
+			Context("When error msg is a plain text", func() {
+				BeforeEach(func() {
+					apiServer.RouteToHandler("DELETE", urlpath,
+						ghttp.RespondWith(http.StatusBadGateway, "502 bad gateway"),
+					)
+				})
+
+				It("Fail with 502 error", func() {
+					err = apihelper.DeleteCredential()
+					Expect(err).Should(HaveOccurred())
+					Expect(err).Should(MatchError("502 bad gateway"))
+				})
+			})
+
+		})
+
 		Context("Get Aggregated Metrics", func() {
 			var urlpath = "/v1/apps/" + fakeAppId + "/aggregated_metric_histories/memoryused"
 			var now int64
@@ -618,6 +845,24 @@ var _ = Describe("API Helper Test", func() {
 						}
 
 					})
+
+					It("succeed with typed records", func() {
+
+						next, records, err := apihelper.GetAggregatedMetricRecords("memoryused", 0, 0, false, uint64(1))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(next).To(BeTrue())
+						Expect(records).To(Equal(metrics[0:10]))
+
+						next, records, err = apihelper.GetAggregatedMetricRecords("memoryused", 0, 0, false, uint64(2))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(next).To(BeTrue())
+						Expect(records).To(Equal(metrics[10:20]))
+
+						next, records, err = apihelper.GetAggregatedMetricRecords("memoryused", 0, 0, false, uint64(3))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(next).To(BeFalse())
+						Expect(records).To(Equal(metrics[20:30]))
+					})
 				})
 
 				Context("Query multiple pages with order asc", func() {
@@ -948,6 +1193,15 @@ var _ = Describe("API Helper Test", func() {
 						Expect(data[2][5]).To(Equal("fakeError"))
 
 					})
+
+					It("succeed with typed records kept intact", func() {
+						next, records, err := apihelper.GetHistoryRecords(0, 0, false, uint64(1))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(next).To(BeFalse())
+						Expect(records).To(Equal(histories_ut[0:3]))
+						Expect(records[1].Reason).To(Equal("fakeReason"))
+						Expect(records[1].Message).To(Equal("fakeMsg"))
+					})
 				})
 
 				Context("Query multiple pages with order desc", func() {
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+// certPools caches the pools of loadCertPool as a client is created for each
+// request, keyed by caCert and the environment
+var certPools sync.Map
+
+// loadCertPool returns the system pool with the certificates of SSL_CERT_FILE,
+// SSL_CERT_DIR and caCert added, or nil for the system pool if there are none.
+// The environment is read explicitly as the system pool ignores it on some
+// platforms.
+func loadCertPool(caCert string) (*x509.CertPool, error) {
+
+	key := caCert + "\x00" + os.Getenv("SSL_CERT_FILE") + "\x00" + os.Getenv("SSL_CERT_DIR")
+	if pool, ok := certPools.Load(key); ok {
+		return pool.(*x509.CertPool), nil
+	}
+
+	pool, err := newCertPool(caCert)
+	if err != nil {
+		return nil, err
+	}
+	certPools.Store(key, pool)
+	return pool, nil
+
+}
+
+func newCertPool(caCert string) (*x509.CertPool, error) {
+
+	certFiles := envCertFiles()
+	if caCert == "" && len(certFiles) == 0 {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	// unreadable files of the environment are ignored like Go does
+	for _, certFile := range certFiles {
+		if content, err := os.ReadFile(certFile); err == nil {
+			pool.AppendCertsFromPEM(content)
+		}
+	}
+
+	if caCert != "" {
+		content, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf(ui.InvalidCACert, caCert, err)
+		}
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf(ui.InvalidCACert, caCert, "no PEM encoded certificate found")
+		}
+	}
+	return pool, nil
+
+}
+
+// envCertFiles lists SSL_CERT_FILE and the files in the directories of
+// SSL_CERT_DIR
+func envCertFiles() []string {
+
+	certFiles := []string{}
+	if certFile := os.Getenv("SSL_CERT_FILE"); certFile != "" {
+		certFiles = append(certFiles, certFile)
+	}
+	for _, certDir := range filepath.SplitList(os.Getenv("SSL_CERT_DIR")) {
+		entries, err := os.ReadDir(certDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				certFiles = append(certFiles, filepath.Join(certDir, entry.Name()))
+			}
+		}
+	}
+	return certFiles
+
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 
 	cf_client "github.com/cloudfoundry/go-cfclient/v3/client"
 	cf_client_config "github.com/cloudfoundry/go-cfclient/v3/config"
@@ -15,6 +17,13 @@ type CFAPIClient struct {
 	client *cf_client.Client
 }
 
+// AppSummary is an app with the instance count of its web process.
+type AppSummary struct {
+	GUID      string
+	Name      string
+	Instances int
+}
+
 func NewCFAPIClient(ccAPIEndpoint *url.URL, authToken string, skipTLSValidation bool) (*CFAPIClient, error) {
 	// A refresh token is not provided by the CF CLI Plugin API and is not required as
 	// "AccessToken() now provides a refreshed o-auth token.",
@@ -57,3 +66,153 @@ func (client *CFAPIClient) GetAppGUID(appName string, currentSpaceGUID string) (
 	app := apps[0]
 	return app.GUID, nil
 }
+
+// GetOrgGUID looks up an organization by name.
+func (client *CFAPIClient) GetOrgGUID(orgName string) (string, error) {
+	orgFilter := &cf_client.OrganizationListOptions{
+		Names: cf_client.Filter{Values: []string{orgName}},
+	}
+	orgs, err := client.client.Organizations.ListAll(context.Background(), orgFilter)
+	if err != nil {
+		return "", err
+	}
+	if len(orgs) == 0 {
+		return "", fmt.Errorf(ui.NoOrg, orgName)
+	}
+	return orgs[0].GUID, nil
+}
+
+// GetSpaceGUID looks up a space by name in an organization.
+func (client *CFAPIClient) GetSpaceGUID(spaceName string, orgGUID string, orgName string) (string, error) {
+	spaceFilter := &cf_client.SpaceListOptions{
+		Names:             cf_client.Filter{Values: []string{spaceName}},
+		OrganizationGUIDs: cf_client.Filter{Values: []string{orgGUID}},
+	}
+	spaces, err := client.client.Spaces.ListAll(context.Background(), spaceFilter)
+	if err != nil {
+		return "", err
+	}
+	if len(spaces) == 0 {
+		return "", fmt.Errorf(ui.NoSpace, spaceName, orgName)
+	}
+	return spaces[0].GUID, nil
+}
+
+// ListApps lists the apps of the space, only the apps matching the label
+// selector if it is not empty.
+func (client *CFAPIClient) ListApps(spaceGUID string, labelSelector string) ([]*AppSummary, error) {
+	appFilter := &cf_client.AppListOptions{
+		SpaceGUIDs: cf_client.Filter{Values: []string{spaceGUID}},
+	}
+	if labelSelector != "" {
+		selector, err := parseLabelSelector(labelSelector)
+		if err != nil {
+			return nil, err
+		}
+		appFilter.ListOptions = cf_client.NewListOptions()
+		appFilter.LabelSel = selector
+	}
+	apps, err := client.client.Applications.ListAll(context.Background(), appFilter)
+	if err != nil || len(apps) == 0 {
+		return nil, err
+	}
+
+	processFilter := &cf_client.ProcessListOptions{
+		SpaceGUIDs: cf_client.Filter{Values: []string{spaceGUID}},
+		Types:      cf_client.Filter{Values: []string{"web"}},
+	}
+	processes, err := client.client.Processes.ListAll(context.Background(), processFilter)
+	if err != nil {
+		return nil, err
+	}
+	instances := map[string]int{}
+	for _, process := range processes {
+		if process.Relationships.App.Data != nil {
+			instances[process.Relationships.App.Data.GUID] = process.Instances
+		}
+	}
+
+	summaries := make([]*AppSummary, len(apps))
+	for i, app := range apps {
+		summaries[i] = &AppSummary{GUID: app.GUID, Name: app.Name, Instances: instances[app.GUID]}
+	}
+	return summaries, nil
+}
+
+// GetAppInstances returns the instance count of the web process of an app.
+func (client *CFAPIClient) GetAppInstances(appGUID string) (int, error) {
+	processFilter := &cf_client.ProcessListOptions{
+		AppGUIDs: cf_client.Filter{Values: []string{appGUID}},
+		Types:    cf_client.Filter{Values: []string{"web"}},
+	}
+	processes, err := client.client.Processes.ListAll(context.Background(), processFilter)
+	if err != nil || len(processes) == 0 {
+		return 0, err
+	}
+	return processes[0].Instances, nil
+}
+
+var setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// parseLabelSelector parses a label selector of the CF API like
+// "env=prod,tier in (web,worker),!legacy", a label can be used once.
+func parseLabelSelector(labelSelector string) (cf_client.LabelSelector, error) {
+	// requirements are separated by commas outside of parentheses
+	var requirements []string
+	depth, start := 0, 0
+	for i, c := range labelSelector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, labelSelector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	requirements = append(requirements, labelSelector[start:])
+
+	selector := cf_client.LabelSelector{}
+	for _, requirement := range requirements {
+		requirement = strings.TrimSpace(requirement)
+		var key string
+		switch {
+		case setRequirement.MatchString(requirement):
+			match := setRequirement.FindStringSubmatch(requirement)
+			var values []string
+			for _, value := range strings.Split(match[3], ",") {
+				values = append(values, strings.TrimSpace(value))
+			}
+			key = match[1]
+			if match[2] == "in" {
+				selector.EqualTo(key, values...)
+			} else {
+				selector.NotEqualTo(key, values...)
+			}
+		case strings.Contains(requirement, "!="):
+			parts := strings.SplitN(requirement, "!=", 2)
+			key = strings.TrimSpace(parts[0])
+			selector.NotEqualTo(key, strings.TrimSpace(parts[1]))
+		case strings.Contains(requirement, "="):
+			parts := strings.SplitN(strings.Replace(requirement, "==", "=", 1), "=", 2)
+			key = strings.TrimSpace(parts[0])
+			selector.EqualTo(key, strings.TrimSpace(parts[1]))
+		case strings.HasPrefix(requirement, "!"):
+			key = strings.TrimSpace(requirement[1:])
+			selector.NotExistence(key)
+		default:
+			key = requirement
+			selector.Existence(key)
+		}
+		if key == "" || strings.ContainsAny(key, " \t()!=") {
+			return nil, fmt.Errorf(ui.InvalidLabelSelector, labelSelector, requirement)
+		}
+	}
+	if len(selector) != len(requirements) {
+		return nil, fmt.Errorf(ui.DuplicateLabelSelector, labelSelector)
+	}
+	return selector, nil
+}
@@ -16,13 +16,30 @@ type CFClient struct {
 	AppId         string
 	AppName       string
 	IsSSLDisabled bool
+	SpaceGuid     string
+	SpaceName     string
+	// Target overrides the org and space targeted with the CF CLI
+	Target Target
+
+	cfAPIClient *CFAPIClient
+}
+
+// Target resolves apps in another org and space than the targeted one, an
+// empty Org is the targeted org. With an AppGUID the app is not looked up by
+// name and no space is needed.
+type Target struct {
+	Org     string
+	Space   string
+	AppGUID string
 }
 
 type Connection interface {
 	ApiEndpoint() (string, error)
+	HasOrganization() (bool, error)
 	HasSpace() (bool, error)
 	IsLoggedIn() (bool, error)
 	AccessToken() (string, error)
+	GetCurrentOrg() (plugin_models.Organization, error)
 	GetCurrentSpace() (plugin_models.Space, error)
 	IsSSLDisabled() (bool, error)
 }
@@ -54,11 +71,52 @@ func NewCFClient(connection Connection) (*CFClient, error) {
 
 func (client *CFClient) Configure(appName string) error {
 
-	if connected, err := client.connection.IsLoggedIn(); !connected {
+	if client.Target.AppGUID != "" {
+		err := client.checkLoggedIn()
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf(ui.LoginRequired, client.CCAPIEndpoint)
+		err = client.configureAPI()
+		if err != nil {
+			return err
+		}
+		client.AppId = client.Target.AppGUID
+		client.AppName = appName
+		return nil
+	}
+
+	err := client.ConfigureSpace()
+	if err != nil {
+		return err
+	}
+
+	appGUID, err := client.cfAPIClient.GetAppGUID(appName, client.SpaceGuid)
+	if err != nil {
+		return err
+	}
+
+	client.AppId = appGUID
+	client.AppName = appName
+	return nil
+
+}
+
+// ConfigureSpace checks that the user is logged in and targets a space,
+// without resolving an app. The space of the Target is looked up instead of
+// the targeted space if set.
+func (client *CFClient) ConfigureSpace() error {
+
+	err := client.checkLoggedIn()
+	if err != nil {
+		return err
+	}
+
+	if client.Target.Org != "" || client.Target.Space != "" {
+		err = client.configureAPI()
+		if err != nil {
+			return err
+		}
+		return client.configureTargetSpace()
 	}
 
 	if hasSpace, err := client.connection.HasSpace(); !hasSpace {
@@ -73,29 +131,112 @@ func (client *CFClient) Configure(appName string) error {
 		return err
 	}
 
-	authToken, err := client.connection.AccessToken()
+	err = client.configureAPI()
 	if err != nil {
 		return err
 	}
 
-	ccAPIURL, err := url.Parse(client.CCAPIEndpoint)
+	client.SpaceGuid = currentSpace.Guid
+	client.SpaceName = currentSpace.Name
+	return nil
+
+}
+
+// configureTargetSpace looks up the space of the Target in its org, or in
+// the targeted org
+func (client *CFClient) configureTargetSpace() error {
+
+	if client.Target.Space == "" {
+		return fmt.Errorf(ui.OrgWithoutSpace)
+	}
+
+	var orgGUID, orgName string
+	if client.Target.Org != "" {
+		guid, err := client.cfAPIClient.GetOrgGUID(client.Target.Org)
+		if err != nil {
+			return err
+		}
+		orgGUID, orgName = guid, client.Target.Org
+	} else {
+		if hasOrg, err := client.connection.HasOrganization(); !hasOrg {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf(ui.NoOrgTarget)
+		}
+		currentOrg, err := client.connection.GetCurrentOrg()
+		if err != nil {
+			return err
+		}
+		orgGUID, orgName = currentOrg.Guid, currentOrg.Name
+	}
+
+	spaceGUID, err := client.cfAPIClient.GetSpaceGUID(client.Target.Space, orgGUID, orgName)
 	if err != nil {
 		return err
 	}
 
-	cfAPIClient, err := NewCFAPIClient(ccAPIURL, authToken, client.IsSSLDisabled)
+	client.SpaceGuid = spaceGUID
+	client.SpaceName = client.Target.Space
+	return nil
+
+}
+
+func (client *CFClient) checkLoggedIn() error {
+	if connected, err := client.connection.IsLoggedIn(); !connected {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf(ui.LoginRequired, client.CCAPIEndpoint)
+	}
+	return nil
+}
+
+// configureAPI sets up the client of the CF API with the token of the user
+func (client *CFClient) configureAPI() error {
+
+	authToken, err := client.connection.AccessToken()
+	if err != nil {
+		return err
+	}
+
+	ccAPIURL, err := url.Parse(client.CCAPIEndpoint)
 	if err != nil {
 		return err
 	}
 
-	appGUID, err := cfAPIClient.GetAppGUID(appName, currentSpace.Guid)
+	cfAPIClient, err := NewCFAPIClient(ccAPIURL, authToken, client.IsSSLDisabled)
 	if err != nil {
 		return err
 	}
 
+	client.cfAPIClient = cfAPIClient
 	client.AuthToken = authToken
-	client.AppId = appGUID
-	client.AppName = appName
 	return nil
 
 }
+
+// ListApps lists the apps of the targeted space, ConfigureSpace must be called first.
+func (client *CFClient) ListApps() ([]*AppSummary, error) {
+	return client.cfAPIClient.ListApps(client.SpaceGuid, "")
+}
+
+// ListAppsByLabel lists the apps of the targeted space matching the label
+// selector, ConfigureSpace must be called first.
+func (client *CFClient) ListAppsByLabel(labelSelector string) ([]*AppSummary, error) {
+	return client.cfAPIClient.ListApps(client.SpaceGuid, labelSelector)
+}
+
+// GetAppInstances returns the instance count of the configured app, Configure
+// must be called first.
+func (client *CFClient) GetAppInstances() (int, error) {
+	return client.cfAPIClient.GetAppInstances(client.AppId)
+}
+
+// ForApp returns a copy of the configured client for another app of the space.
+func (client *CFClient) ForApp(appGUID string, appName string) *CFClient {
+	appClient := *client
+	appClient.AppId = appGUID
+	appClient.AppName = appName
+	return &appClient
+}
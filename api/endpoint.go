@@ -17,6 +17,18 @@ import (
 type APIEndpoint struct {
 	URL               string
 	SkipSSLValidation bool
+	// CACert is the path of a PEM file with the CA certificate to trust
+	CACert  string `json:",omitempty"`
+	Profile string `json:",omitempty"`
+}
+
+// endpointConfig is the content of ConfigFile, the AutoScaler API endpoints
+// keyed by the CF API endpoint they belong to. URL and SkipSSLValidation are
+// the single endpoint stored by former versions of the plugin.
+type endpointConfig struct {
+	Endpoints         map[string]*APIEndpoint
+	URL               string `json:",omitempty"`
+	SkipSSLValidation bool   `json:",omitempty"`
 }
 
 var ConfigFile = func() string {
@@ -31,17 +43,53 @@ var ConfigFile = func() string {
 	return filepath.Join(targetsPath, defaultConfigFileName)
 }
 
-func UnsetEndpoint() error {
+// UnsetEndpoint removes the endpoint of the current CF API, the endpoints of
+// other CF APIs are kept.
+func UnsetEndpoint(cfclient *CFClient) error {
 
-	configFilePath := ConfigFile()
-	err := ioutil.WriteFile(configFilePath, nil, 0600)
+	config, err := readEndpointConfig()
 	if err != nil {
 		return err
 	}
-	return nil
+	delete(config.Endpoints, endpointKey(cfclient.CCAPIEndpoint))
+	return writeEndpointConfig(config)
+}
+
+// RemoveEndpoint removes the endpoint of a profile or of a CF API and returns
+// the CF API it belonged to.
+func RemoveEndpoint(name string) (string, error) {
+
+	config, err := readEndpointConfig()
+	if err != nil {
+		return "", err
+	}
+	ccAPIEndpoint := findEndpoint(config, name)
+	if ccAPIEndpoint == "" {
+		return "", fmt.Errorf(ui.NoAPIEndpointFor, name)
+	}
+	delete(config.Endpoints, ccAPIEndpoint)
+	return ccAPIEndpoint, writeEndpointConfig(config)
 }
 
-func SetEndpoint(cfclient *CFClient, url string, skipSSLValidation bool) error {
+// ListEndpoints returns the endpoints of all CF APIs keyed by the CF API.
+func ListEndpoints() (map[string]*APIEndpoint, error) {
+
+	config, err := readEndpointConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config.Endpoints, nil
+}
+
+// EndpointKey is the key of the endpoints of a CF API in ListEndpoints.
+func EndpointKey(cfclient *CFClient) string {
+	return endpointKey(cfclient.CCAPIEndpoint)
+}
+
+// SetEndpoint stores the endpoint of the current CF API, named as profile if
+// not empty, or else keeping the profile of the endpoint it replaces. caCert is
+// the path of a CA certificate to trust, if any.
+func SetEndpoint(cfclient *CFClient, url string, skipSSLValidation bool, caCert string, profile string) error {
 
 	cfDomain := getDomain(cfclient.CCAPIEndpoint)
 	autoscalerDomain := getDomain(url)
@@ -49,52 +97,83 @@ func SetEndpoint(cfclient *CFClient, url string, skipSSLValidation bool) error {
 		return fmt.Errorf(ui.InconsistentDomain, url, cfclient.CCAPIEndpoint)
 	}
 
+	config, err := readEndpointConfig()
+	if err != nil {
+		return err
+	}
+	key := endpointKey(cfclient.CCAPIEndpoint)
+	err = checkProfile(config, key, profile)
+	if err != nil {
+		return err
+	}
+	if existing, ok := config.Endpoints[key]; ok && profile == "" {
+		profile = existing.Profile
+	}
+
 	skipSSLValidation = skipSSLValidation || cfclient.IsSSLDisabled
 	endpoint := &APIEndpoint{
 		URL:               strings.TrimSuffix(url, "/"),
 		SkipSSLValidation: skipSSLValidation,
+		CACert:            caCert,
+		Profile:           profile,
 	}
 
 	apihelper := NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
-	err := apihelper.CheckHealth()
+	err = apihelper.CheckHealth()
 	if err != nil {
 		return err
 	}
-	urlConfig, err := json.Marshal(endpoint)
+
+	config.Endpoints[key] = endpoint
+	return writeEndpointConfig(config)
+}
+
+// NameEndpoint names the endpoint of the current CF API as profile.
+func NameEndpoint(cfclient *CFClient, profile string) error {
+
+	endpoint, err := GetEndpoint(cfclient)
 	if err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(ConfigFile(), urlConfig, 0600)
+	if endpoint.URL == "" {
+		return fmt.Errorf(ui.NoEndpoint)
+	}
+
+	config, err := readEndpointConfig()
 	if err != nil {
 		return err
 	}
-
-	return nil
+	key := endpointKey(cfclient.CCAPIEndpoint)
+	err = checkProfile(config, key, profile)
+	if err != nil {
+		return err
+	}
+	config.Endpoints[key].Profile = profile
+	return writeEndpointConfig(config)
 }
 
+// GetEndpoint returns the endpoint of the current CF API. Without one, the
+// endpoint of former versions of the plugin is taken over if it is in the
+// domain of the CF API, or else the default endpoint is set if it works.
 func GetEndpoint(cfclient *CFClient) (*APIEndpoint, error) {
 
-	endpoint, err := getEndpointFromConfig()
+	config, err := readEndpointConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	if endpoint.URL != "" {
-		cfDomain := getDomain(cfclient.CCAPIEndpoint)
-		autoscalerDomain := getDomain(endpoint.URL)
-		if cfDomain != autoscalerDomain {
-			UnsetEndpoint()
-			endpoint = &APIEndpoint{}
-		}
+	key := endpointKey(cfclient.CCAPIEndpoint)
+	if endpoint, ok := config.Endpoints[key]; ok && endpoint.URL != "" {
+		return endpoint, nil
 	}
 
-	if endpoint.URL == "" {
-		endpoint, err = getDefaultEndpoint(cfclient)
-		if err != nil {
-			return nil, err
-		}
+	if config.URL != "" && getDomain(cfclient.CCAPIEndpoint) == getDomain(config.URL) {
+		endpoint := &APIEndpoint{URL: config.URL, SkipSSLValidation: config.SkipSSLValidation}
+		config.Endpoints[key] = endpoint
+		return endpoint, writeEndpointConfig(config)
 	}
-	return endpoint, nil
+
+	return getDefaultEndpoint(cfclient)
 
 }
 
@@ -104,15 +183,25 @@ func getDefaultEndpoint(cfclient *CFClient) (*APIEndpoint, error) {
 	asAPIURL := strings.Replace(ccAPIURL, "api.", "autoscaler.", 1)
 
 	//ignore all erros here if the default value won't work
-	SetEndpoint(cfclient, asAPIURL, cfclient.IsSSLDisabled)
-	return getEndpointFromConfig()
+	SetEndpoint(cfclient, asAPIURL, cfclient.IsSSLDisabled, "", "")
+
+	config, err := readEndpointConfig()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint, ok := config.Endpoints[endpointKey(ccAPIURL)]; ok {
+		return endpoint, nil
+	}
+	return &APIEndpoint{}, nil
 
 }
 
-func getEndpointFromConfig() (*APIEndpoint, error) {
+// readEndpointConfig reads ConfigFile, a missing or invalid file has no
+// endpoints
+func readEndpointConfig() (*endpointConfig, error) {
 
 	configFilePath := ConfigFile()
-	endpoint := &APIEndpoint{}
+	config := &endpointConfig{}
 
 	if _, err := os.Stat(configFilePath); os.IsNotExist(err) {
 		err := ioutil.WriteFile(configFilePath, nil, 0600)
@@ -124,12 +213,70 @@ func getEndpointFromConfig() (*APIEndpoint, error) {
 		if err != nil {
 			return nil, err
 		}
-		err = json.Unmarshal(content, &endpoint)
-		if err != nil || endpoint.URL == "" {
-			ioutil.WriteFile(configFilePath, nil, 0600)
+		err = json.Unmarshal(content, config)
+		if err != nil {
+			config = &endpointConfig{}
 		}
 	}
-	return endpoint, nil
+	if config.Endpoints == nil {
+		config.Endpoints = map[string]*APIEndpoint{}
+	}
+	return config, nil
+
+}
+
+// writeEndpointConfig writes ConfigFile, the endpoint of former versions is
+// dropped once the endpoints are written
+func writeEndpointConfig(config *endpointConfig) error {
+
+	content, err := json.Marshal(&endpointConfig{Endpoints: config.Endpoints})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ConfigFile(), content, 0600)
+
+}
+
+// findEndpoint returns the CF API of the endpoint with the profile name, or
+// of the CF API name
+func findEndpoint(config *endpointConfig, name string) string {
+
+	for ccAPIEndpoint, endpoint := range config.Endpoints {
+		if endpoint.Profile != "" && endpoint.Profile == name {
+			return ccAPIEndpoint
+		}
+	}
+	if _, ok := config.Endpoints[endpointKey(name)]; ok {
+		return endpointKey(name)
+	}
+	return ""
+
+}
+
+// checkProfile fails if the profile names the endpoint of another CF API
+func checkProfile(config *endpointConfig, key string, profile string) error {
+
+	if profile == "" {
+		return nil
+	}
+	for ccAPIEndpoint, endpoint := range config.Endpoints {
+		if ccAPIEndpoint != key && endpoint.Profile == profile {
+			return fmt.Errorf(ui.ProfileInUse, profile, ccAPIEndpoint)
+		}
+	}
+	return nil
+
+}
+
+// endpointKey normalizes a CF API endpoint the way the URL of an AutoScaler
+// API endpoint is
+func endpointKey(ccAPIEndpoint string) string {
+
+	ccAPIEndpoint = strings.TrimSuffix(ccAPIEndpoint, "/")
+	if !strings.HasPrefix(ccAPIEndpoint, "http") {
+		ccAPIEndpoint = "https://" + ccAPIEndpoint
+	}
+	return ccAPIEndpoint
 
 }
 
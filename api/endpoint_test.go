@@ -12,6 +12,7 @@ import (
 	"github.com/onsi/gomega/ghttp"
 
 	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
 )
 
 var _ = Describe("Endpoint Helper Test", func() {
@@ -33,6 +34,7 @@ var _ = Describe("Endpoint Helper Test", func() {
 	BeforeEach(func() {
 		os.Setenv("AUTOSCALER_CONFIG_FILE", "test_config.json")
 		configFilePath = ConfigFile()
+		os.Remove(configFilePath)
 		cliConnection = &pluginfakes.FakeCliConnection{}
 	})
 
@@ -56,23 +58,43 @@ var _ = Describe("Endpoint Helper Test", func() {
 
 		Context("When endpoint is valid", func() {
 			BeforeEach(func() {
-				err = SetEndpoint(cfclient, apiServer.URL()+"/", false)
+				err = SetEndpoint(cfclient, apiServer.URL()+"/", false, "", "")
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("Set a valid json to config file", func() {
-				err = SetEndpoint(cfclient, apiServer.URL(), false)
+				err = SetEndpoint(cfclient, apiServer.URL(), false, "", "")
 				Expect(err).NotTo(HaveOccurred())
 
 				content, err = ioutil.ReadFile(configFilePath)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(content).Should(MatchJSON(fmt.Sprintf(`{"URL":"%s", "SkipSSLValidation":%t}`, apiServer.URL(), false)))
+				Expect(content).Should(MatchJSON(fmt.Sprintf(`{"Endpoints":{"%s":{"URL":"%s", "SkipSSLValidation":%t}}}`, apiServer.URL(), apiServer.URL(), false)))
 			})
 
 			It("it prune the last /", func() {
 				content, err = ioutil.ReadFile(configFilePath)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(content).Should(MatchJSON(fmt.Sprintf(`{"URL":"%s", "SkipSSLValidation":%t}`, apiServer.URL(), false)))
+				Expect(content).Should(MatchJSON(fmt.Sprintf(`{"Endpoints":{"%s":{"URL":"%s", "SkipSSLValidation":%t}}}`, apiServer.URL(), apiServer.URL(), false)))
+			})
+
+			It("names the endpoint as a profile and keeps the profile when set again", func() {
+				err = SetEndpoint(cfclient, apiServer.URL(), false, "", "dev")
+				Expect(err).NotTo(HaveOccurred())
+				err = SetEndpoint(cfclient, apiServer.URL(), true, "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				endpoints, err := ListEndpoints()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoints).To(HaveKeyWithValue(apiServer.URL(), &APIEndpoint{URL: apiServer.URL(), SkipSSLValidation: true, Profile: "dev"}))
+			})
+
+			It("fails with a profile used for another CF API", func() {
+				urlConfig := []byte(`{"Endpoints":{"https://api.bosh-lite.com":{"URL":"https://autoscaler.bosh-lite.com","Profile":"dev"}}}`)
+				err = ioutil.WriteFile(configFilePath, urlConfig, 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = SetEndpoint(cfclient, apiServer.URL(), false, "", "dev")
+				Expect(err).To(MatchError(fmt.Sprintf(ui.ProfileInUse, "dev", "https://api.bosh-lite.com")))
 			})
 		})
 
@@ -84,7 +106,7 @@ var _ = Describe("Endpoint Helper Test", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 			It("it fails", func() {
-				err = SetEndpoint(cfclient, apiServer.URL(), false)
+				err = SetEndpoint(cfclient, apiServer.URL(), false, "", "")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -97,27 +119,55 @@ var _ = Describe("Endpoint Helper Test", func() {
 			})
 
 			It("it fails", func() {
-				err = SetEndpoint(cfclient, apiServer.URL(), false)
+				err = SetEndpoint(cfclient, apiServer.URL(), false, "", "")
 				Expect(err).To(HaveOccurred())
 			})
 		})
 	})
 
-	Context("Unset API endpoint", func() {
+	Context("Unset and remove API endpoints", func() {
 
 		BeforeEach(func() {
-			urlConfig := []byte(fmt.Sprintf(`{"URL":"%s"}`, fakeApiEndpoint))
+			urlConfig := []byte(fmt.Sprintf(`{"Endpoints":{
+				"https://api.boshlite.com":{"URL":"https://%s"},
+				"https://api.bosh-lite.com":{"URL":"https://autoscaler.bosh-lite.com","Profile":"lite"}}}`, fakeApiEndpoint))
 			err = ioutil.WriteFile(configFilePath, urlConfig, 0600)
 			Expect(err).NotTo(HaveOccurred())
+
+			cliConnection.ApiEndpointReturns("https://api.boshlite.com/", nil)
+			cfclient, err = NewCFClient(cliConnection)
+			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("Succed and set config.json to an empty file", func() {
-			err = UnsetEndpoint()
+		It("Succeed and only removes the endpoint of the current CF API", func() {
+			err = UnsetEndpoint(cfclient)
 			Expect(err).NotTo(HaveOccurred())
 
 			content, err = ioutil.ReadFile(configFilePath)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(len(content)).Should(Equal(0))
+			Expect(content).Should(MatchJSON(`{"Endpoints":{"https://api.bosh-lite.com":{"URL":"https://autoscaler.bosh-lite.com","SkipSSLValidation":false,"Profile":"lite"}}}`))
+		})
+
+		It("removes the endpoint of a profile", func() {
+			ccAPIEndpoint, err := RemoveEndpoint("lite")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ccAPIEndpoint).To(Equal("https://api.bosh-lite.com"))
+
+			endpoints, err := ListEndpoints()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(endpoints).To(HaveLen(1))
+			Expect(endpoints).To(HaveKey("https://api.boshlite.com"))
+		})
+
+		It("removes the endpoint of a CF API", func() {
+			ccAPIEndpoint, err := RemoveEndpoint("api.boshlite.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ccAPIEndpoint).To(Equal("https://api.boshlite.com"))
+		})
+
+		It("fails for an unknown profile", func() {
+			_, err = RemoveEndpoint("prod")
+			Expect(err).To(MatchError(fmt.Sprintf(ui.NoAPIEndpointFor, "prod")))
 		})
 	})
 
@@ -157,6 +207,21 @@ var _ = Describe("Endpoint Helper Test", func() {
 					Expect(err).NotTo(HaveOccurred())
 				})
 
+				It("keeps the endpoint of the other CF API", func() {
+					urlConfig := []byte(`{"Endpoints":{"https://api.bosh-lite.com":{"URL":"https://autoscaler.bosh-lite.com"}}}`)
+					err = ioutil.WriteFile(configFilePath, urlConfig, 0600)
+					Expect(err).NotTo(HaveOccurred())
+
+					endpoint, err = GetEndpoint(cfclient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(endpoint.URL).Should(Equal(apiServer.URL()))
+
+					endpoints, err := ListEndpoints()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(endpoints).To(HaveKeyWithValue("https://api.bosh-lite.com", &APIEndpoint{URL: "https://autoscaler.bosh-lite.com"}))
+					Expect(endpoints).To(HaveKeyWithValue(apiServer.URL(), &APIEndpoint{URL: apiServer.URL()}))
+				})
+
 				It("Clear staled setting and return the default autoscaler endpoint if it does work ", func() {
 					endpoint, err = GetEndpoint(cfclient)
 					Expect(err).NotTo(HaveOccurred())
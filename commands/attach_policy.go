@@ -6,27 +6,58 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+	"go.yaml.in/yaml/v3"
 )
 
+// StdinPath can be given instead of a policy file to read the policy from stdin
+const StdinPath = "-"
+
 type AttachPolicyCommand struct {
 	RequiredlArgs AttachPolicyPositionalArgs `positional-args:"yes"`
+	DryRun        bool                       `long:"dry-run" description:"show what would change without attaching the policy"`
+	Apps          string                     `long:"apps" description:"attach the policy to the apps of the targeted space matching the comma-separated glob patterns instead of APP_NAME, e.g. 'orders-*,payments'"`
+	LabelSelector string                     `long:"label-selector" description:"attach the policy to the apps of the targeted space matching the label selector instead of APP_NAME, e.g. 'team=checkout,env in (prod,staging)'"`
+	Policy        string                     `long:"policy" value-name:"PATH_TO_POLICY_FILE" description:"policy file to attach with --apps or --label-selector"`
 }
 
 type AttachPolicyPositionalArgs struct {
-	AppName    string `positional-arg-name:"APP_NAME" required:"true" `
-	PolicyFile string `positional-arg-name:"PATH_TO_POLICY_FILE" required:"true"`
+	AppName    string `positional-arg-name:"APP_NAME"`
+	PolicyFile string `positional-arg-name:"PATH_TO_POLICY_FILE"`
 }
 
 func (command AttachPolicyCommand) Execute([]string) error {
-	return CreatePolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.RequiredlArgs.PolicyFile)
+	// the arguments are checked here as there are none with --apps and
+	// --label-selector, the policy file is given with --policy then
+	if command.Apps != "" || command.LabelSelector != "" {
+		if command.RequiredlArgs.AppName != "" {
+			return errors.New(ui.AppNameWithBulkAttach)
+		}
+		if command.Policy == "" {
+			return errors.New(ui.PolicyOptionRequired)
+		}
+		return BulkCreatePolicy(AutoScaler.CLIConnection, command.Apps, command.LabelSelector, command.Policy, command.DryRun, os.Stdout)
+	}
+	if command.Policy != "" {
+		return errors.New(ui.PolicyWithAppName)
+	}
+	if command.RequiredlArgs.AppName == "" {
+		return errors.New(ui.AttachArgsRequired)
+	}
+	if command.RequiredlArgs.PolicyFile == "" {
+		return errors.New(ui.PolicyFileRequired)
+	}
+	return CreatePolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.RequiredlArgs.PolicyFile, command.DryRun)
 }
 
-func CreatePolicy(cliConnection api.Connection, appName string, policyFile string) error {
+func CreatePolicy(cliConnection api.Connection, appName string, policyFile string, dryRun bool) error {
 
-	cfclient, err := api.NewCFClient(cliConnection)
+	cfclient, err := newCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
@@ -45,22 +76,171 @@ func CreatePolicy(cliConnection api.Connection, appName string, policyFile strin
 
 	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
 
+	if dryRun {
+		return dryRunCreatePolicy(apihelper, appName, policyFile)
+	}
+
 	ui.SayMessage(ui.AttachPolicyHint, appName)
-	contents, err := ioutil.ReadFile(policyFile)
+	policy, _, err := loadPolicyFile(policyFile)
 	if err != nil {
-		return fmt.Errorf(ui.FailToLoadPolicyFile, policyFile)
+		return err
 	}
-	var policy map[string]interface{}
-	err = json.Unmarshal(contents, &policy)
+
+	err = apihelper.CreatePolicy(policy)
 	if err != nil {
-		return fmt.Errorf(ui.InvalidPolicy, err)
+		return err
 	}
 
-	err = apihelper.CreatePolicy(policy)
+	ui.SayOK()
+	return nil
+}
+
+// dryRunCreatePolicy shows the changes attaching the policy would make,
+// everything but the PUT request is done.
+func dryRunCreatePolicy(apihelper *api.APIHelper, appName string, policyFile string) error {
+
+	ui.SayMessage(ui.DryRunAttachPolicyHint, appName)
+	_, filePolicy, err := loadPolicyFile(policyFile)
 	if err != nil {
 		return err
 	}
 
+	attachedPolicy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return err
+	}
+	if attachedPolicy == nil {
+		ui.SayMessage(ui.NoPolicyAttached, appName)
+	}
+
+	sayPolicyChanges(models.DiffPolicies(attachedPolicy, filePolicy))
+
 	ui.SayOK()
+	ui.SayWarningMessage(ui.DryRunWarning)
 	return nil
 }
+
+// loadPolicyFile reads and validates a policy file. The policy is also
+// returned as the raw JSON document, so that fields unknown to the plugin are
+// kept when it is attached.
+func loadPolicyFile(policyFile string) (map[string]interface{}, *models.ScalingPolicy, error) {
+
+	policy, scalingPolicy, err := readPolicyFile(policyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if errs := scalingPolicy.Validate(); errs != nil {
+		return nil, nil, fmt.Errorf(ui.InvalidPolicy, "\n"+errs.Error())
+	}
+
+	return policy, scalingPolicy, nil
+}
+
+// readPolicyFile parses a policy file both as a raw JSON document and as a
+// typed policy without validating it. Files with a .yml or .yaml extension
+// are converted to JSON first. The policy is read from stdin if the path is
+// StdinPath, it is parsed as JSON if it is valid JSON and as YAML otherwise.
+func readPolicyFile(policyFile string) (map[string]interface{}, *models.ScalingPolicy, error) {
+
+	var (
+		contents []byte
+		err      error
+	)
+	if policyFile == StdinPath {
+		contents, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		contents, err = ioutil.ReadFile(policyFile)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf(ui.FailToLoadPolicyFile, policyFile)
+	}
+	if policyFile == StdinPath {
+		return parsePolicy(contents, !json.Valid(contents))
+	}
+	return parsePolicy(contents, isYAMLFile(policyFile))
+}
+
+// parsePolicy parses a JSON or YAML policy document like readPolicyFile
+func parsePolicy(contents []byte, isYAML bool) (map[string]interface{}, *models.ScalingPolicy, error) {
+
+	var err error
+	if isYAML {
+		contents, err = yamlToJSON(contents)
+		if err != nil {
+			return nil, nil, fmt.Errorf(ui.InvalidPolicy, err)
+		}
+	}
+	var policy map[string]interface{}
+	err = json.Unmarshal(contents, &policy)
+	if err != nil {
+		return nil, nil, fmt.Errorf(ui.InvalidPolicy, err)
+	}
+
+	var scalingPolicy models.ScalingPolicy
+	err = json.Unmarshal(contents, &scalingPolicy)
+	if err != nil {
+		return nil, nil, fmt.Errorf(ui.InvalidPolicy, err)
+	}
+
+	return policy, &scalingPolicy, nil
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// yamlToJSON converts a YAML policy to JSON. YAML resolves unquoted scalars
+// like 2026-01-01 or +1 to timestamps and numbers, the string fields of the
+// policy keep their source text as decoding the policy itself does.
+func yamlToJSON(contents []byte) ([]byte, error) {
+	var document interface{}
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy models.ScalingPolicy
+	err = yaml.Unmarshal(contents, &policy)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(&policy)
+	if err != nil {
+		return nil, err
+	}
+	var typed interface{}
+	err = json.Unmarshal(content, &typed)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(keepStringFields(document, typed))
+}
+
+// keepStringFields replaces the values of the document with the strings of
+// the typed document at the same place. Fields unknown to the policy are
+// left as they are.
+func keepStringFields(document interface{}, typed interface{}) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		if fields, ok := typed.(map[string]interface{}); ok {
+			for key := range value {
+				if field, ok := fields[key]; ok {
+					value[key] = keepStringFields(value[key], field)
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := typed.([]interface{}); ok && len(items) == len(value) {
+			for i := range value {
+				value[i] = keepStringFields(value[i], items[i])
+			}
+		}
+	default:
+		if text, ok := typed.(string); ok && document != nil {
+			return text
+		}
+	}
+	return document
+}
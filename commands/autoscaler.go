@@ -1,20 +1,44 @@
 package commands
 
 import (
+	"fmt"
+
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
 )
 
 type AutoScalerCmds struct {
 	CLIConnection api.Connection
+	Target        TargetOptions `group:"Target Options"`
 
-	API          ApiCommand          `command:"autoscaling-api" description:"Set or view AutoScaler service API endpoint"`
-	Policy       PolicyCommand       `command:"autoscaling-policy" description:"Retrieve the scaling policy of an application"`
-	AttachPolicy AttachPolicyCommand `command:"attach-autoscaling-policy" description:"Attach a scaling policy to an application"`
-	DetachPolicy DetachPolicyCommand `command:"detach-autoscaling-policy" description:"Detach a scaling policy from an application"`
-	Metrics      MetricsCommand      `command:"autoscaling-metrics" description:"Retrieve the metrics of an application"`
-	History      HistoryCommand      `command:"autoscaling-history" description:"Retrieve the history of an application"`
+	API              ApiCommand              `command:"autoscaling-api" description:"Set or view AutoScaler service API endpoint"`
+	Policy           PolicyCommand           `command:"autoscaling-policy" description:"Retrieve the scaling policy of an application"`
+	AttachPolicy     AttachPolicyCommand     `command:"attach-autoscaling-policy" description:"Attach a scaling policy to an application"`
+	DetachPolicy     DetachPolicyCommand     `command:"detach-autoscaling-policy" description:"Detach a scaling policy from an application"`
+	EditPolicy       EditPolicyCommand       `command:"edit-autoscaling-policy" description:"Edit the scaling policy of an application with $EDITOR"`
+	ExportPolicies   ExportPoliciesCommand   `command:"export-autoscaling-policies" description:"Export the scaling policies of all applications in the targeted space"`
+	ImportPolicies   ImportPoliciesCommand   `command:"import-autoscaling-policies" description:"Attach exported scaling policies to the applications of the targeted space"`
+	ValidatePolicy   ValidatePolicyCommand   `command:"validate-autoscaling-policy" description:"Validate a scaling policy file without attaching it"`
+	DiffPolicy       DiffPolicyCommand       `command:"autoscaling-policy-diff" description:"Compare the scaling policy of an application with a policy file"`
+	Metrics          MetricsCommand          `command:"autoscaling-metrics" description:"Retrieve the metrics of an application"`
+	History          HistoryCommand          `command:"autoscaling-history" description:"Retrieve the history of an application"`
+	CreateCredential CreateCredentialCommand `command:"create-autoscaling-credential" description:"Create custom metric credential for an application"`
+	DeleteCredential DeleteCredentialCommand `command:"delete-autoscaling-credential" description:"Delete the custom metric credential of an application"`
+	Apps             AppsCommand             `command:"autoscaling-apps" description:"List the apps of the targeted space with their autoscaling settings"`
+	Report           ReportCommand           `command:"autoscaling-report" description:"Report the scaling behavior of an application"`
+	SimulatePolicy   SimulatePolicyCommand   `command:"autoscaling-simulate" description:"Simulate a scaling policy against the metrics of an application"`
+	Schedule         ScheduleCommand         `command:"autoscaling-schedule" description:"Preview the upcoming schedule windows of a scaling policy"`
 
 	UninstallPlugin UninstallHook `command:"CLI-MESSAGE-UNINSTALL"`
 }
 
 var AutoScaler AutoScalerCmds
+
+// ExitCodeError ends the plugin with a non-zero exit code without reporting a
+// failure, the command has already printed its outcome.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
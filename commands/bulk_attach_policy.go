@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+const (
+	transferAttached  = "attached"
+	transferChanges   = "would change"
+	transferUnchanged = "unchanged"
+)
+
+// BulkCreatePolicy attaches the policy file to the apps of the targeted space
+// matching the comma-separated glob patterns and the label selector. The
+// endpoint, the space and the policy are checked once, the policy is then
+// attached to the apps concurrently. Apps failing to attach are reported and
+// do not stop the others.
+func BulkCreatePolicy(cliConnection api.Connection, patterns string, labelSelector string, policyFile string, dryRun bool, writer io.Writer) error {
+
+	var globs []string
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf(ui.InvalidAppPattern, pattern)
+		}
+		globs = append(globs, pattern)
+	}
+
+	policy, filePolicy, err := loadPolicyFile(policyFile)
+	if err != nil {
+		return err
+	}
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.ConfigureSpace()
+	if err != nil {
+		return err
+	}
+
+	apps, err := cfclient.ListAppsByLabel(labelSelector)
+	if err != nil {
+		return err
+	}
+	apps = matchApps(apps, globs)
+	if len(apps) == 0 {
+		return fmt.Errorf(ui.NoAppsMatched, cfclient.SpaceName)
+	}
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	if dryRun {
+		ui.SayMessage(ui.DryRunBulkAttachHint, policyFile, len(apps), cfclient.SpaceName)
+	} else {
+		ui.SayMessage(ui.BulkAttachHint, policyFile, len(apps), cfclient.SpaceName)
+	}
+	err = api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE")).CheckHealth()
+	if err != nil {
+		return err
+	}
+
+	transfers := make([]*policyTransfer, len(apps))
+	appNames := make([]string, len(apps))
+	semaphore := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		appNames[i] = app.Name
+		wg.Add(1)
+		go func(i int, app *api.AppSummary) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			apihelper := api.NewAPIHelper(endpoint, cfclient.ForApp(app.GUID, app.Name), os.Getenv("CF_TRACE"))
+			apihelper.HealthChecked = true
+			if dryRun {
+				transfers[i] = dryRunAttachPolicy(apihelper, filePolicy)
+			} else {
+				transfers[i] = attachPolicy(apihelper, policy)
+			}
+		}(i, app)
+	}
+	wg.Wait()
+
+	counts := sayPolicyTransfers(writer, appNames, transfers)
+	if dryRun {
+		ui.SayMessage(ui.DryRunBulkAttachSummary, counts[transferChanges], counts[transferUnchanged], counts[transferFailed])
+		ui.SayWarningMessage(ui.DryRunWarning)
+	} else {
+		ui.SayMessage(ui.BulkAttachSummary, counts[transferAttached], counts[transferFailed])
+	}
+	if counts[transferFailed] > 0 {
+		return &ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+// matchApps keeps the apps whose name matches any of the glob patterns, all
+// apps without patterns
+func matchApps(apps []*api.AppSummary, globs []string) []*api.AppSummary {
+	if len(globs) == 0 {
+		return apps
+	}
+	var matched []*api.AppSummary
+	for _, app := range apps {
+		for _, glob := range globs {
+			if ok, _ := path.Match(glob, app.Name); ok {
+				matched = append(matched, app)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func attachPolicy(apihelper *api.APIHelper, policy map[string]interface{}) *policyTransfer {
+	err := apihelper.CreatePolicy(policy)
+	if err != nil {
+		return &policyTransfer{status: transferFailed, details: err.Error()}
+	}
+	return &policyTransfer{status: transferAttached}
+}
+
+func dryRunAttachPolicy(apihelper *api.APIHelper, filePolicy *models.ScalingPolicy) *policyTransfer {
+	attachedPolicy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return &policyTransfer{status: transferFailed, details: err.Error()}
+	}
+	changes := models.DiffPolicies(attachedPolicy, filePolicy)
+	if len(changes) == 0 {
+		return &policyTransfer{status: transferUnchanged}
+	}
+	if attachedPolicy == nil {
+		return &policyTransfer{status: transferChanges, details: fmt.Sprintf(ui.NoPolicyAttached, apihelper.Client.AppName)}
+	}
+	return &policyTransfer{status: transferChanges, details: fmt.Sprintf(ui.PolicyDiffers, len(changes))}
+}
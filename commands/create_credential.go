@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type CreateCredentialCommand struct {
+	RequiredlArgs  CreateCredentialPositionalArgs `positional-args:"yes"`
+	CredentialFile string                         `long:"credential-file" description:"use the username and password defined in a JSON file instead of generating random ones"`
+	Output         string                         `long:"output" description:"dump the credential to a file in JSON format"`
+}
+
+type CreateCredentialPositionalArgs struct {
+	AppName string `positional-arg-name:"APP_NAME" required:"true"`
+}
+
+func (command CreateCredentialCommand) Execute([]string) error {
+	return CreateCredential(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.CredentialFile, os.Stdout, command.Output)
+}
+
+func CreateCredential(cliConnection api.Connection, appName string, credentialFile string, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+
+	// the credential is written to a temporary file next to the output file
+	// and only replaces it once created, the temporary file is created first
+	// to fail before creating a credential that cannot be saved
+	var tempFile *os.File
+	if outputfile != "" {
+		tempFile, err = os.CreateTemp(filepath.Dir(outputfile), "."+filepath.Base(outputfile)+"-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tempFile.Name())
+		defer tempFile.Close()
+		writer = tempFile
+		ui.SayMessage(ui.SaveCredentialHint, appName, outputfile)
+	} else {
+		ui.SayMessage(ui.CreateCredentialHint, appName)
+	}
+
+	// Without a credential file the server generates a random username and password.
+	var credential interface{}
+	if credentialFile != "" {
+		contents, err := ioutil.ReadFile(credentialFile)
+		if err != nil {
+			return fmt.Errorf(ui.FailToLoadCredentialFile, credentialFile)
+		}
+		var userCredential models.Credential
+		err = json.Unmarshal(contents, &userCredential)
+		if err != nil {
+			return fmt.Errorf(ui.InvalidCredential, err)
+		}
+		if userCredential.Username == "" || userCredential.Password == "" {
+			return fmt.Errorf(ui.InvalidCredential, "both username and password are required")
+		}
+		credential = userCredential
+	}
+
+	result, err := apihelper.CreateCredential(credential)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "%v", string(result))
+	if err != nil {
+		return err
+	}
+
+	if outputfile != "" {
+		err = tempFile.Close()
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tempFile.Name(), outputfile)
+		if err != nil {
+			return err
+		}
+		ui.SayOK()
+	}
+	ui.SayWarningMessage(ui.CreateCredentialWarning, appName)
+	return nil
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
@@ -10,6 +11,7 @@ import (
 
 type DetachPolicyCommand struct {
 	RequiredlArgs DetachPolicyPositionalArgs `positional-args:"yes"`
+	DryRun        bool                       `long:"dry-run" description:"show the policy that would be detached without detaching it"`
 }
 
 type DetachPolicyPositionalArgs struct {
@@ -17,12 +19,12 @@ type DetachPolicyPositionalArgs struct {
 }
 
 func (command DetachPolicyCommand) Execute([]string) error {
-	return DetachPolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName)
+	return DetachPolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.DryRun)
 }
 
-func DetachPolicy(cliConnection api.Connection, appName string) error {
+func DetachPolicy(cliConnection api.Connection, appName string, dryRun bool) error {
 
-	cfclient, err := api.NewCFClient(cliConnection)
+	cfclient, err := newCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
@@ -42,6 +44,20 @@ func DetachPolicy(cliConnection api.Connection, appName string) error {
 
 	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
 
+	if dryRun {
+		ui.SayMessage(ui.DryRunDetachPolicyHint, appName)
+		policy, err := apihelper.GetPolicy()
+		if err != nil {
+			return err
+		}
+		ui.SayMessage(ui.DetachedPolicy)
+		fmt.Printf("%v", string(policy))
+
+		ui.SayOK()
+		ui.SayWarningMessage(ui.DryRunWarning)
+		return nil
+	}
+
 	ui.SayMessage(ui.DetachPolicyHint, appName)
 	err = apihelper.DeletePolicy()
 	if err != nil {
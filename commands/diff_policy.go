@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type DiffPolicyCommand struct {
+	RequiredlArgs DiffPolicyPositionalArgs `positional-args:"yes"`
+}
+
+type DiffPolicyPositionalArgs struct {
+	AppName    string `positional-arg-name:"APP_NAME" required:"true" `
+	PolicyFile string `positional-arg-name:"PATH_TO_POLICY_FILE" required:"true"`
+}
+
+func (command DiffPolicyCommand) Execute([]string) error {
+	return DiffPolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.RequiredlArgs.PolicyFile)
+}
+
+// DiffPolicy compares the attached policy with a policy file, it returns an
+// ExitCodeError when they differ so that the command can gate pipelines.
+func DiffPolicy(cliConnection api.Connection, appName string, policyFile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+
+	ui.SayMessage(ui.DiffPolicyHint, appName, policyFile)
+	_, filePolicy, err := readPolicyFile(policyFile)
+	if err != nil {
+		return err
+	}
+
+	attachedPolicy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return err
+	}
+	if attachedPolicy == nil {
+		return fmt.Errorf(ui.PolicyNotFound, appName)
+	}
+
+	changes := models.DiffPolicies(attachedPolicy, filePolicy)
+	sayPolicyChanges(changes)
+	if len(changes) > 0 {
+		return &ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+func sayPolicyChanges(changes []models.PolicyChange) {
+	if len(changes) == 0 {
+		ui.SayMessage(ui.PolicyIdentical)
+		return
+	}
+
+	for _, change := range changes {
+		switch change.Type {
+		case models.PolicyChangeAdded:
+			ui.SayAdded("%s: %s", change.Path, change.Description)
+		case models.PolicyChangeRemoved:
+			ui.SayRemoved("%s: %s", change.Path, change.Description)
+		default:
+			ui.SayChanged("%s: %s", change.Path, change.Description)
+		}
+	}
+	ui.SayMessage(ui.PolicyDiffers, len(changes))
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type EditPolicyCommand struct {
+	RequiredlArgs EditPolicyPositionalArgs `positional-args:"yes"`
+	Format        string                   `long:"format" choice:"json" choice:"yaml" default:"yaml" description:"format of the policy in the editor"`
+}
+
+type EditPolicyPositionalArgs struct {
+	AppName string `positional-arg-name:"APP_NAME" required:"true"`
+}
+
+func (command EditPolicyCommand) Execute([]string) error {
+	return EditPolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.Format, os.Stdin)
+}
+
+// editorComment starts the lines of the editor file that are dropped when the
+// policy is read back, also in JSON
+const editorComment = "#"
+
+// EditPolicy opens the attached policy in the editor of the user, validates
+// the edited policy, shows the changes and attaches it once confirmed on
+// stdin. The editor is reopened with the error as a comment if the edited
+// policy is invalid or rejected by the AutoScaler API.
+func EditPolicy(cliConnection api.Connection, appName string, format string, stdin io.Reader) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+
+	ui.SayMessage(ui.EditPolicyHint, appName)
+	policy, err := apihelper.GetPolicy()
+	if err != nil {
+		return err
+	}
+	if format == ui.FormatYAML {
+		policy, err = policyToYAML(policy)
+		if err != nil {
+			return err
+		}
+	}
+	_, attachedPolicy, err := parsePolicy(policy, format == ui.FormatYAML)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "autoscaling-policy-*."+format)
+	if err != nil {
+		return err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	answers := bufio.NewReader(stdin)
+	problem := ""
+	for {
+		err = os.WriteFile(file.Name(), editorContent(appName, problem, policy), 0600)
+		if err != nil {
+			return err
+		}
+		err = runEditor(file.Name())
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(file.Name())
+		if err != nil {
+			return err
+		}
+
+		// leaving the editor without changes cancels, also after an error
+		edited := stripEditorComments(contents)
+		if len(bytes.TrimSpace(edited)) == 0 || bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(policy)) {
+			ui.SayMessage(ui.EditPolicyCancelled)
+			if problem != "" {
+				return errors.New(problem)
+			}
+			return nil
+		}
+		policy = edited
+
+		document, editedPolicy, err := parsePolicy(policy, format == ui.FormatYAML)
+		if err == nil {
+			if errs := editedPolicy.Validate(); errs != nil {
+				err = fmt.Errorf(ui.InvalidPolicy, "\n"+errs.Error())
+			}
+		}
+		if err != nil {
+			problem = err.Error()
+			continue
+		}
+		problem = ""
+
+		changes := models.DiffPolicies(attachedPolicy, editedPolicy)
+		sayPolicyChanges(changes)
+		if len(changes) == 0 {
+			ui.SayMessage(ui.EditPolicyCancelled)
+			return nil
+		}
+		fmt.Printf(ui.ConfirmAttachPolicy, appName)
+		answer, _ := answers.ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			ui.SayMessage(ui.EditPolicyCancelled)
+			return nil
+		}
+
+		ui.SayMessage(ui.AttachPolicyHint, appName)
+		err = apihelper.CreatePolicy(document)
+		var invalidPolicy *api.InvalidPolicyError
+		if errors.As(err, &invalidPolicy) {
+			ui.SayMessage("%s", err.Error())
+			problem = err.Error()
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		ui.SayOK()
+		return nil
+	}
+}
+
+// editorContent prepends the policy with instructions and the problem with
+// the last edit as comments, the way kubectl edit does
+func editorContent(appName string, problem string, policy []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(fmt.Sprintf(ui.EditPolicyInstructions, appName), "\n") {
+		fmt.Fprintf(&buf, "%s %s\n", editorComment, line)
+	}
+	if problem != "" {
+		fmt.Fprintf(&buf, "%s\n", editorComment)
+		for _, line := range strings.Split(problem, "\n") {
+			fmt.Fprintf(&buf, "%s %s\n", editorComment, line)
+		}
+	}
+	buf.Write(policy)
+	return buf.Bytes()
+}
+
+func stripEditorComments(contents []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(string(contents), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), editorComment) {
+			buf.WriteString(line)
+		}
+	}
+	return buf.Bytes()
+}
+
+// runEditor opens the file in $EDITOR, the editor may have arguments like
+// "code --wait"
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf(ui.EditorFailed, strings.Join(editor, " "), err)
+	}
+	return nil
+}
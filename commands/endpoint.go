@@ -1,6 +1,12 @@
 package commands
 
 import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
@@ -9,24 +15,41 @@ import (
 
 type ApiCommand struct {
 	OptionalArgs      APIPositionalArgs `positional-args:"yes"`
-	Unset             bool              `long:"unset" description:"Unset the api endpoint"`
+	Unset             bool              `long:"unset" description:"Unset the api endpoint of the current CF api"`
 	SkipSSLValidation bool              `long:"skip-ssl-validation" description:"Skip verification of the API endpoint. Not recommended!"`
+	CACert            string            `long:"ca-cert" value-name:"PATH" description:"Trust the CA certificate in a PEM file to verify the API endpoint"`
+	Name              string            `long:"name" value-name:"PROFILE" description:"Name the api endpoint of the current CF api as a profile"`
+	List              bool              `long:"list" description:"List the api endpoints of all CF apis"`
+	Remove            string            `long:"remove" value-name:"PROFILE" description:"Remove the api endpoint of a profile or a CF api"`
 }
 
 type APIPositionalArgs struct {
 	URL string `positional-arg-name:"URL" description:"The autoscaler API endpoint"`
 }
 
+var apiEndpointHeaders = []string{"", "Profile", "CF API", "AutoScaler API", "Skip SSL Validation", "CA Certificate"}
+
 func (cmd ApiCommand) Execute([]string) error {
 
+	if cmd.CACert != "" && cmd.SkipSSLValidation {
+		return errors.New(ui.CACertWithSkipSSL)
+	}
 	if cmd.Unset {
-		return cmd.UnsetEndpoint()
+		return cmd.UnsetEndpoint(AutoScaler.CLIConnection)
 	}
-	if cmd.OptionalArgs.URL == "" {
-		return cmd.GetEndpoint(AutoScaler.CLIConnection)
-	} else {
-		return cmd.SetEndpoint(AutoScaler.CLIConnection, cmd.OptionalArgs.URL, cmd.SkipSSLValidation)
+	if cmd.List {
+		return cmd.ListEndpoints(AutoScaler.CLIConnection, os.Stdout)
+	}
+	if cmd.Remove != "" {
+		return cmd.RemoveEndpoint(cmd.Remove)
 	}
+	if cmd.OptionalArgs.URL != "" {
+		return cmd.SetEndpoint(AutoScaler.CLIConnection, cmd.OptionalArgs.URL, cmd.SkipSSLValidation, cmd.CACert, cmd.Name)
+	}
+	if cmd.Name != "" {
+		return cmd.NameEndpoint(AutoScaler.CLIConnection, cmd.Name)
+	}
+	return cmd.GetEndpoint(AutoScaler.CLIConnection)
 }
 
 func (cmd *ApiCommand) GetEndpoint(cliConnection api.Connection) error {
@@ -48,26 +71,108 @@ func (cmd *ApiCommand) GetEndpoint(cliConnection api.Connection) error {
 	return nil
 }
 
-func (cmd *ApiCommand) UnsetEndpoint() error {
+func (cmd *ApiCommand) UnsetEndpoint(cliConnection api.Connection) error {
+
+	cfclient, err := api.NewCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
 
 	ui.SayMessage(ui.UnsetAPIEndpoint)
 
-	err := api.UnsetEndpoint()
+	err = api.UnsetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	ui.SayOK()
+	return nil
+
+}
+
+// ListEndpoints lists the endpoints of all CF APIs, marking the one of the
+// current CF API if a CF API is set
+func (cmd *ApiCommand) ListEndpoints(cliConnection api.Connection, writer io.Writer) error {
+
+	current := ""
+	if cfclient, err := api.NewCFClient(cliConnection); err == nil {
+		current = api.EndpointKey(cfclient)
+	}
+
+	endpoints, err := api.ListEndpoints()
 	if err != nil {
 		return err
 	}
+	if len(endpoints) == 0 {
+		ui.SayMessage(ui.NoAPIEndpoints)
+		return nil
+	}
+
+	ui.SayMessage(ui.ListAPIEndpoints)
+	ccAPIEndpoints := make([]string, 0, len(endpoints))
+	for ccAPIEndpoint := range endpoints {
+		ccAPIEndpoints = append(ccAPIEndpoints, ccAPIEndpoint)
+	}
+	sort.Strings(ccAPIEndpoints)
+
+	table := ui.NewTable(writer, apiEndpointHeaders)
+	for _, ccAPIEndpoint := range ccAPIEndpoints {
+		marker := ""
+		if ccAPIEndpoint == current {
+			marker = "*"
+		}
+		endpoint := endpoints[ccAPIEndpoint]
+		table.Add([]string{marker, endpoint.Profile, ccAPIEndpoint, endpoint.URL, strconv.FormatBool(endpoint.SkipSSLValidation), endpoint.CACert})
+	}
 	ui.SayOK()
+	table.Print()
 	return nil
 
 }
 
-func (cmd *ApiCommand) SetEndpoint(cliConnection api.Connection, url string, skipSSLValidation bool) error {
+func (cmd *ApiCommand) RemoveEndpoint(name string) error {
+
+	ccAPIEndpoint, err := api.RemoveEndpoint(name)
+	if err != nil {
+		return err
+	}
+	ui.SayMessage(ui.RemoveAPIEndpoint, ccAPIEndpoint)
+	ui.SayOK()
+	return nil
+
+}
+
+func (cmd *ApiCommand) NameEndpoint(cliConnection api.Connection, profile string) error {
 
 	cfclient, err := api.NewCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
 
+	ui.SayMessage(ui.NameAPIEndpoint, cfclient.CCAPIEndpoint, profile)
+	err = api.NameEndpoint(cfclient, profile)
+	if err != nil {
+		return err
+	}
+	ui.SayOK()
+	return nil
+
+}
+
+func (cmd *ApiCommand) SetEndpoint(cliConnection api.Connection, url string, skipSSLValidation bool, caCert string, profile string) error {
+
+	cfclient, err := api.NewCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	// the certificate is read on each invocation, from any working directory
+	if caCert != "" {
+		caCert, err = filepath.Abs(caCert)
+		if err != nil {
+			return err
+		}
+	}
+
 	if strings.HasSuffix(url, "/") {
 		url = strings.TrimSuffix(url, "/")
 	}
@@ -76,7 +181,7 @@ func (cmd *ApiCommand) SetEndpoint(cliConnection api.Connection, url string, ski
 	}
 
 	ui.SayMessage(ui.SetAPIEndpoint, url)
-	err = api.SetEndpoint(cfclient, url, skipSSLValidation)
+	err = api.SetEndpoint(cfclient, url, skipSSLValidation, caCert, profile)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+// PolicyIndexFile lists the policies written by export-autoscaling-policies
+const PolicyIndexFile = "index.json"
+
+type ExportPoliciesCommand struct {
+	Dir string `long:"dir" default:"autoscaling-policies" description:"directory to write the policies and the index to, it is created if needed"`
+}
+
+func (command ExportPoliciesCommand) Execute([]string) error {
+	return ExportPolicies(AutoScaler.CLIConnection, command.Dir, os.Stdout)
+}
+
+// policyIndex is the content of PolicyIndexFile, File is relative to the
+// directory of the index
+type policyIndex struct {
+	Space      string              `json:"space"`
+	ExportedAt string              `json:"exported_at"`
+	Policies   []*policyIndexEntry `json:"policies"`
+}
+
+type policyIndexEntry struct {
+	AppName string `json:"app_name"`
+	AppGUID string `json:"app_guid"`
+	File    string `json:"file"`
+}
+
+// policyTransfer is the outcome of exporting or importing the policy of an app
+type policyTransfer struct {
+	status  string
+	details string
+}
+
+const (
+	transferExported = "exported"
+	transferImported = "imported"
+	transferNoPolicy = "no policy"
+	transferFailed   = "failed"
+)
+
+var policyTransferHeaders = []string{"App Name", "Status", "Details"}
+
+// ExportPolicies writes the policy of every app in the targeted space to a
+// file named after the app, and an index of the files. Apps failing to export
+// are reported and do not stop the export.
+func ExportPolicies(cliConnection api.Connection, dir string, writer io.Writer) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.ConfigureSpace()
+	if err != nil {
+		return err
+	}
+
+	ui.SayMessage(ui.ExportPoliciesHint, cfclient.SpaceName, dir)
+	apps, err := cfclient.ListApps()
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.AppsNotFound, cfclient.SpaceName)
+		return nil
+	}
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	err = api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE")).CheckHealth()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	files := policyFileNames(apps)
+	transfers := make([]*policyTransfer, len(apps))
+	semaphore := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		wg.Add(1)
+		go func(i int, app *api.AppSummary) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			apihelper := api.NewAPIHelper(endpoint, cfclient.ForApp(app.GUID, app.Name), os.Getenv("CF_TRACE"))
+			apihelper.HealthChecked = true
+			transfers[i] = exportPolicy(apihelper, dir, files[i])
+		}(i, app)
+	}
+	wg.Wait()
+
+	index := &policyIndex{
+		Space:      cfclient.SpaceName,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Policies:   []*policyIndexEntry{},
+	}
+	for i, app := range apps {
+		if transfers[i].status == transferExported {
+			index.Policies = append(index.Policies, &policyIndexEntry{AppName: app.Name, AppGUID: app.GUID, File: transfers[i].details})
+		}
+	}
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(dir, PolicyIndexFile), append(content, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	appNames := make([]string, len(apps))
+	for i, app := range apps {
+		appNames[i] = app.Name
+	}
+	counts := sayPolicyTransfers(writer, appNames, transfers)
+	ui.SayMessage(ui.ExportPoliciesSummary, counts[transferExported], filepath.Join(dir, PolicyIndexFile),
+		counts[transferNoPolicy], counts[transferFailed])
+	if counts[transferFailed] > 0 {
+		return &ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+func exportPolicy(apihelper *api.APIHelper, dir string, file string) *policyTransfer {
+	policy, err := apihelper.GetPolicy()
+	var notFound *api.PolicyNotFoundError
+	if errors.As(err, &notFound) {
+		return &policyTransfer{status: transferNoPolicy}
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, file), append(policy, '\n'), 0644)
+	}
+	if err != nil {
+		return &policyTransfer{status: transferFailed, details: err.Error()}
+	}
+	return &policyTransfer{status: transferExported, details: file}
+}
+
+// policyFileNames names the policy file of each app after the app, path
+// separators are replaced as app names may contain them. Names clashing with
+// an earlier one or with PolicyIndexFile, also only in case for
+// case-insensitive file systems, get a numbered suffix.
+func policyFileNames(apps []*api.AppSummary) []string {
+	files := make([]string, len(apps))
+	used := map[string]bool{strings.ToLower(PolicyIndexFile): true}
+	for i, app := range apps {
+		base := strings.NewReplacer("/", "_", "\\", "_").Replace(app.Name)
+		file := base + ".json"
+		for n := 2; used[strings.ToLower(file)]; n++ {
+			file = fmt.Sprintf("%s-%d.json", base, n)
+		}
+		used[strings.ToLower(file)] = true
+		files[i] = file
+	}
+	return files
+}
+
+// sayPolicyTransfers prints the outcome per app and counts the apps by
+// status
+func sayPolicyTransfers(writer io.Writer, appNames []string, transfers []*policyTransfer) map[string]int {
+	counts := map[string]int{}
+	table := ui.NewTable(writer, policyTransferHeaders)
+	for i, appName := range appNames {
+		counts[transfers[i].status]++
+		table.Add([]string{appName, transfers[i].status, strings.ReplaceAll(transfers[i].details, "\n", " ")})
+	}
+
+	if counts[transferFailed] == 0 {
+		ui.SayOK()
+	}
+	table.Print()
+	return counts
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"strings"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+)
+
+// HistoryFilterOptions are the options of autoscaling-history narrowing the
+// scaling events, an empty option matches every event.
+type HistoryFilterOptions struct {
+	Status         string `long:"status" choice:"succeeded" choice:"failed" description:"only show scaling events with the given status"`
+	Type           string `long:"type" choice:"dynamic" choice:"scheduled" description:"only show scaling events of the given scaling type"`
+	Direction      string `long:"direction" choice:"out" choice:"in" description:"only show scaling events adding (out) or removing (in) instances"`
+	ReasonContains string `long:"reason-contains" description:"only show scaling events whose reason or message contains the given text, case-insensitive"`
+}
+
+// active tells whether any filter is given
+func (options HistoryFilterOptions) active() bool {
+	return options.Status != "" || options.Type != "" || options.Direction != "" || options.ReasonContains != ""
+}
+
+// matches checks a scaling event against all given filters
+func (options HistoryFilterOptions) matches(history *models.AppScalingHistory) bool {
+	switch options.Status {
+	case "succeeded":
+		if history.Status != models.ScalingStatusSucceeded {
+			return false
+		}
+	case "failed":
+		if history.Status != models.ScalingStatusFailed {
+			return false
+		}
+	}
+
+	switch options.Type {
+	case "dynamic":
+		if history.ScalingType != models.ScalingTypeDynamic {
+			return false
+		}
+	case "scheduled":
+		if history.ScalingType != models.ScalingTypeScheduled {
+			return false
+		}
+	}
+
+	switch options.Direction {
+	case "out":
+		if history.NewInstances <= history.OldInstances {
+			return false
+		}
+	case "in":
+		if history.NewInstances >= history.OldInstances {
+			return false
+		}
+	}
+
+	if options.ReasonContains != "" {
+		text := strings.ToLower(options.ReasonContains)
+		if !strings.Contains(strings.ToLower(history.Reason), text) &&
+			!strings.Contains(strings.ToLower(history.Message), text) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter keeps the scaling events matching the options in their order
+func (options HistoryFilterOptions) filter(histories []*models.AppScalingHistory) []*models.AppScalingHistory {
+	if !options.active() {
+		return histories
+	}
+	var matched []*models.AppScalingHistory
+	for _, history := range histories {
+		if options.matches(history) {
+			matched = append(matched, history)
+		}
+	}
+	return matched
+}
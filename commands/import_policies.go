@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type ImportPoliciesCommand struct {
+	RequiredlArgs ImportPoliciesPositionalArgs `positional-args:"yes"`
+}
+
+type ImportPoliciesPositionalArgs struct {
+	Dir string `positional-arg-name:"DIR" required:"true"`
+}
+
+func (command ImportPoliciesCommand) Execute([]string) error {
+	return ImportPolicies(AutoScaler.CLIConnection, command.RequiredlArgs.Dir, os.Stdout)
+}
+
+// ImportPolicies attaches the policies listed in the index of an export to
+// the apps of the targeted space with the same name. Apps failing to import
+// are reported and do not stop the import.
+func ImportPolicies(cliConnection api.Connection, dir string, writer io.Writer) error {
+
+	contents, err := os.ReadFile(filepath.Join(dir, PolicyIndexFile))
+	if err != nil {
+		return fmt.Errorf(ui.FailToLoadPolicyIndex, filepath.Join(dir, PolicyIndexFile))
+	}
+	var index policyIndex
+	err = json.Unmarshal(contents, &index)
+	if err != nil {
+		return fmt.Errorf(ui.InvalidPolicyIndex, filepath.Join(dir, PolicyIndexFile), err)
+	}
+	// the files must stay in the directory of the index
+	for _, entry := range index.Policies {
+		if !filepath.IsLocal(entry.File) {
+			return fmt.Errorf(ui.InvalidPolicyIndex, filepath.Join(dir, PolicyIndexFile), fmt.Sprintf(ui.PolicyFileOutsideDir, entry.File))
+		}
+	}
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.ConfigureSpace()
+	if err != nil {
+		return err
+	}
+
+	ui.SayMessage(ui.ImportPoliciesHint, dir, cfclient.SpaceName)
+	apps, err := cfclient.ListApps()
+	if err != nil {
+		return err
+	}
+	err = api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE")).CheckHealth()
+	if err != nil {
+		return err
+	}
+	// the apps are matched by name, their GUIDs differ across spaces
+	guids := map[string]string{}
+	for _, app := range apps {
+		guids[app.Name] = app.GUID
+	}
+
+	transfers := make([]*policyTransfer, len(index.Policies))
+	appNames := make([]string, len(index.Policies))
+	semaphore := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, entry := range index.Policies {
+		appNames[i] = entry.AppName
+		guid, ok := guids[entry.AppName]
+		if !ok {
+			transfers[i] = &policyTransfer{status: transferFailed, details: fmt.Sprintf(ui.NoApp, entry.AppName)}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, entry *policyIndexEntry, guid string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			apihelper := api.NewAPIHelper(endpoint, cfclient.ForApp(guid, entry.AppName), os.Getenv("CF_TRACE"))
+			apihelper.HealthChecked = true
+			transfers[i] = importPolicy(apihelper, filepath.Join(dir, entry.File))
+		}(i, entry, guid)
+	}
+	wg.Wait()
+
+	counts := sayPolicyTransfers(writer, appNames, transfers)
+	ui.SayMessage(ui.ImportPoliciesSummary, counts[transferImported], counts[transferFailed])
+	if counts[transferFailed] > 0 {
+		return &ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+func importPolicy(apihelper *api.APIHelper, policyFile string) *policyTransfer {
+	policy, _, err := loadPolicyFile(policyFile)
+	if err == nil {
+		err = apihelper.CreatePolicy(policy)
+	}
+	if err != nil {
+		return &policyTransfer{status: transferFailed, details: err.Error()}
+	}
+	return &policyTransfer{status: transferImported, details: filepath.Base(policyFile)}
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+	ctime "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
+)
+
+// maxConcurrentRequests bounds the requests sent to the AutoScaler API at once
+const maxConcurrentRequests = 10
+
+type AppsCommand struct {
+	TimeDisplayOptions
+}
+
+func (command AppsCommand) Execute([]string) error {
+	err := command.TimeDisplayOptions.validate()
+	if err != nil {
+		return err
+	}
+	return ListApps(AutoScaler.CLIConnection, command.TimeDisplayOptions, os.Stdout)
+}
+
+type appOverview struct {
+	policy    *models.ScalingPolicy
+	lastEvent string
+	err       error
+}
+
+func ListApps(cliConnection api.Connection, timeOptions TimeDisplayOptions, writer io.Writer) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+
+	err = cfclient.ConfigureSpace()
+	if err != nil {
+		return err
+	}
+
+	ui.SayMessage(ui.ShowAppsHint, cfclient.SpaceName)
+	apps, err := cfclient.ListApps()
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.AppsNotFound, cfclient.SpaceName)
+		return nil
+	}
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	err = api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE")).CheckHealth()
+	if err != nil {
+		return err
+	}
+
+	overviews := make([]*appOverview, len(apps))
+	semaphore := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		wg.Add(1)
+		go func(i int, app *api.AppSummary) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			apihelper := api.NewAPIHelper(endpoint, cfclient.ForApp(app.GUID, app.Name), os.Getenv("CF_TRACE"))
+			apihelper.HealthChecked = true
+			overviews[i] = getAppOverview(apihelper, timeOptions)
+		}(i, app)
+	}
+	wg.Wait()
+
+	// apps failing to be retrieved show the error instead of the last event
+	failed := 0
+	table := ui.NewTable(writer, []string{"App Name", "Instances", "Min", "Max", "Rules", "Schedules", "Last Scaling Event"})
+	for i, app := range apps {
+		overview := overviews[i]
+		if overview.err != nil {
+			failed++
+			table.Add([]string{app.Name, strconv.Itoa(app.Instances), "-", "-", "-", "-", strings.ReplaceAll(overview.err.Error(), "\n", " ")})
+			continue
+		}
+
+		row := []string{app.Name, strconv.Itoa(app.Instances), "-", "-", "-", "-", overview.lastEvent}
+		if policy := overview.policy; policy != nil {
+			schedules := 0
+			if policy.Schedules != nil {
+				schedules = len(policy.Schedules.RecurringSchedules) + len(policy.Schedules.SpecificDateSchedules)
+			}
+			row[2] = strconv.Itoa(policy.InstanceMin)
+			row[3] = strconv.Itoa(policy.InstanceMax)
+			row[4] = strconv.Itoa(len(policy.ScalingRules))
+			row[5] = strconv.Itoa(schedules)
+		}
+		table.Add(row)
+	}
+
+	if failed == 0 {
+		ui.SayOK()
+	}
+	table.Print()
+	if failed > 0 {
+		ui.SayMessage(ui.AppsOverviewFailed, failed)
+		return &ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+// getAppOverview fetches the policy and, for apps with a policy, the latest
+// scaling event. With the policy time zone, apps without schedules fall back
+// to the local time zone.
+func getAppOverview(apihelper *api.APIHelper, timeOptions TimeDisplayOptions) *appOverview {
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return &appOverview{err: err}
+	}
+	if policy == nil {
+		return &appOverview{lastEvent: "-"}
+	}
+
+	timezone := timeOptions.Timezone
+	if timezone == ctime.TimezonePolicy {
+		timezone = policyTimezone(policy)
+	}
+	apihelper.TimeFormatter, err = ctime.NewFormatter(timezone, timeOptions.TimeFormat)
+	if err != nil {
+		return &appOverview{err: err}
+	}
+
+	_, data, err := apihelper.GetHistory(0, time.Now().UnixNano(), false, 1)
+	if err != nil {
+		return &appOverview{err: err}
+	}
+	lastEvent := "-"
+	if len(data) > 0 {
+		// columns are scaling type, status, instance changes, time, action and error
+		change := data[0][2]
+		if change == "" {
+			change = data[0][1]
+		}
+		lastEvent = data[0][3] + " " + data[0][0] + " " + change
+	}
+
+	return &appOverview{policy: policy, lastEvent: lastEvent}
+}
@@ -0,0 +1,363 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/util/stats"
+)
+
+const (
+	// defaultAnalysisRange is the time range of a report or a simulation
+	// without --since or --start
+	defaultAnalysisRange = "7d"
+
+	reportFormatText = "text"
+	reportFormatJSON = "json"
+)
+
+type ReportCommand struct {
+	RequiredlArgs ReportPositionalArgs `positional-args:"yes"`
+	Since         TimeOption           `long:"since" description:"report on the given duration before now, e.g. 30m, 2h or 7d, cannot be used with --start, default to 7d if neither is specified."`
+	StartTime     TimeOption           `long:"start" description:"start time of the report in the same formats as autoscaling-history."`
+	EndTime       TimeOption           `long:"end" description:"end time of the report in the same formats as --start, default to current time if not specified."`
+	Format        string               `long:"format" choice:"text" choice:"json" default:"text" description:"output format of the report"`
+	Output        string               `long:"output" description:"dump the report to a file in the chosen format"`
+	TimeDisplayOptions
+}
+
+type ReportPositionalArgs struct {
+	AppName string `positional-arg-name:"APP_NAME" required:"true"`
+}
+
+func (command ReportCommand) Execute([]string) error {
+
+	var (
+		err    error
+		writer *os.File
+	)
+	since := command.Since
+	if since == "" && command.StartTime == "" {
+		since = defaultAnalysisRange
+	}
+	st, et, err := parseTimeRange(since, command.StartTime, command.EndTime)
+	if err != nil {
+		return err
+	}
+	err = command.TimeDisplayOptions.validate()
+	if err != nil {
+		return err
+	}
+
+	if command.Output != "" {
+		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+	} else {
+		writer = os.Stdout
+	}
+
+	return ReportScaling(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, st, et, command.Format, command.TimeDisplayOptions, writer, command.Output)
+}
+
+// scalingReport sums up the scaling behavior of an app in a time range. The
+// instance count is derived from the succeeded scaling events, durations are
+// in seconds.
+type scalingReport struct {
+	AppName          string          `json:"app_name"`
+	Start            int64           `json:"start"`
+	End              int64           `json:"end"`
+	ScaleOutEvents   int             `json:"scale_out_events"`
+	ScaleInEvents    int             `json:"scale_in_events"`
+	FailedEvents     int             `json:"failed_events"`
+	FailureRate      float64         `json:"failure_rate"`
+	InstanceHours    float64         `json:"instance_hours"`
+	PeakInstances    int             `json:"peak_instances"`
+	AverageInstances float64         `json:"average_instances"`
+	CoolDownSeconds  int             `json:"cool_down_secs"`
+	FlappingPairs    int             `json:"flapping_pairs"`
+	InstanceMin      int             `json:"instance_min_count"`
+	InstanceMax      int             `json:"instance_max_count"`
+	SecondsAtMin     float64         `json:"secs_at_instance_min_count"`
+	SecondsAtMax     float64         `json:"secs_at_instance_max_count"`
+	Metrics          []*reportMetric `json:"metrics"`
+}
+
+type reportMetric struct {
+	Name  string  `json:"name"`
+	Unit  string  `json:"unit"`
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	P95   float64 `json:"p95"`
+	Max   float64 `json:"max"`
+}
+
+// ReportScaling builds the scaling report of an app from its policy, the
+// scaling history and the metrics of its scaling rules in the time range.
+func ReportScaling(cliConnection api.Connection, appName string, startTime, endTime int64, format string, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveReportHint, appName, outputfile)
+	} else if format == reportFormatText {
+		ui.SayMessage(ui.ShowReportHint, appName,
+			apihelper.TimeFormatter.Format(startTime), apihelper.TimeFormatter.Format(endTime))
+	}
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf(ui.PolicyNotFound, appName)
+	}
+
+	var (
+		events []*models.AppScalingHistory
+		page   uint64 = 1
+	)
+	for {
+		next, histories, err := apihelper.GetHistoryRecords(startTime, endTime, true, page)
+		if err != nil {
+			return err
+		}
+		events = append(events, histories...)
+		if !next {
+			break
+		}
+		page += 1
+	}
+
+	initial, err := initialInstances(apihelper, cfclient, startTime, events)
+	if err != nil {
+		return err
+	}
+
+	report := buildScalingReport(startTime, endTime, initial, events, policy)
+	report.AppName = appName
+
+	series, err := collectMetricSeries(apihelper, policyMetricNames(policy), startTime, endTime)
+	if err != nil {
+		return err
+	}
+	report.Metrics = []*reportMetric{}
+	for _, s := range series {
+		summary := stats.Summarize(s.values)
+		report.Metrics = append(report.Metrics, &reportMetric{
+			Name:  s.name,
+			Unit:  s.unit,
+			Count: summary.Count,
+			Mean:  round(summary.Mean),
+			P95:   round(summary.P95),
+			Max:   summary.Max,
+		})
+	}
+
+	if format == reportFormatJSON {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(writer, string(content))
+	} else {
+		printScalingReport(writer, report, apihelper)
+	}
+
+	if outputfile != "" {
+		ui.SayOK()
+	}
+	return nil
+}
+
+// initialInstances returns the instance count at the start time: the result
+// of the last succeeded scaling before it, the count before the first scaling
+// in the time range or, without any scaling, the current count of the app.
+func initialInstances(apihelper *api.APIHelper, cfclient *api.CFClient, startTime int64, events []*models.AppScalingHistory) (int, error) {
+	if startTime > 0 {
+		_, histories, err := apihelper.GetHistoryRecords(0, startTime-1, false, 1)
+		if err != nil {
+			return 0, err
+		}
+		for _, history := range histories {
+			if history.Status == models.ScalingStatusSucceeded {
+				return history.NewInstances, nil
+			}
+		}
+	}
+	if len(events) > 0 {
+		return events[0].OldInstances, nil
+	}
+
+	return cfclient.GetAppInstances()
+}
+
+// buildScalingReport folds the scaling events in ascending order into the
+// report figures. Failed events leave the instance count unchanged and are
+// not counted as scale-out or scale-in. A flapping pair is a dynamic
+// scale-out followed by a scale-in, or the other way around, within the
+// longest cool-down of the scaling rules.
+func buildScalingReport(startTime, endTime int64, initial int, events []*models.AppScalingHistory, policy *models.ScalingPolicy) *scalingReport {
+	report := &scalingReport{
+		Start:           startTime,
+		End:             endTime,
+		CoolDownSeconds: models.DefaultCoolDownSeconds,
+		InstanceMin:     policy.InstanceMin,
+		InstanceMax:     policy.InstanceMax,
+		PeakInstances:   initial,
+	}
+	coolDown := 0
+	for _, rule := range policy.ScalingRules {
+		if rule != nil {
+			coolDown = max(coolDown, rule.CoolDownSeconds)
+		}
+	}
+	if coolDown > 0 {
+		report.CoolDownSeconds = coolDown
+	}
+
+	var (
+		instances     = initial
+		since         = startTime
+		instanceNanos float64
+		last          *models.AppScalingHistory
+	)
+	// account adds the time from since until the given time at the current
+	// instance count
+	account := func(until int64) {
+		d := float64(until - since)
+		instanceNanos += d * float64(instances)
+		if instances == policy.InstanceMin {
+			report.SecondsAtMin += d / float64(time.Second)
+		}
+		if instances == policy.InstanceMax {
+			report.SecondsAtMax += d / float64(time.Second)
+		}
+		since = until
+	}
+
+	for _, event := range events {
+		if event.Status == models.ScalingStatusFailed {
+			report.FailedEvents++
+			continue
+		}
+		switch {
+		case event.NewInstances > event.OldInstances:
+			report.ScaleOutEvents++
+		case event.NewInstances < event.OldInstances:
+			report.ScaleInEvents++
+		}
+
+		if event.ScalingType == models.ScalingTypeDynamic {
+			if last != nil && scalingDirection(last) == -scalingDirection(event) && scalingDirection(event) != 0 &&
+				event.Timestamp-last.Timestamp <= int64(report.CoolDownSeconds)*int64(time.Second) {
+				report.FlappingPairs++
+			}
+			last = event
+		}
+
+		account(max(min(event.Timestamp, endTime), since))
+		instances = event.NewInstances
+		report.PeakInstances = max(report.PeakInstances, instances)
+	}
+	account(max(endTime, since))
+
+	if len(events) > 0 {
+		report.FailureRate = round(float64(report.FailedEvents) / float64(len(events)))
+	}
+	report.InstanceHours = round(instanceNanos / float64(time.Hour))
+	if endTime > startTime {
+		report.AverageInstances = round(instanceNanos / float64(endTime-startTime))
+	}
+	report.SecondsAtMin = math.Round(report.SecondsAtMin)
+	report.SecondsAtMax = math.Round(report.SecondsAtMax)
+	return report
+}
+
+// scalingDirection is 1 for a scale-out, -1 for a scale-in and 0 otherwise
+func scalingDirection(event *models.AppScalingHistory) int {
+	switch {
+	case event.NewInstances > event.OldInstances:
+		return 1
+	case event.NewInstances < event.OldInstances:
+		return -1
+	}
+	return 0
+}
+
+func printScalingReport(writer io.Writer, report *scalingReport, apihelper *api.APIHelper) {
+	duration := float64(report.End-report.Start) / float64(time.Second)
+	share := func(seconds float64) string {
+		if duration <= 0 {
+			return "0%"
+		}
+		return formatFloat(round(seconds/duration*100)) + "%"
+	}
+
+	table := ui.NewTable(writer, []string{"Figure", "Value"})
+	table.Add([]string{"Time range", apihelper.TimeFormatter.Format(report.Start) + " - " + apihelper.TimeFormatter.Format(report.End)})
+	table.Add([]string{"Scale-out events", strconv.Itoa(report.ScaleOutEvents)})
+	table.Add([]string{"Scale-in events", strconv.Itoa(report.ScaleInEvents)})
+	table.Add([]string{"Failed events", fmt.Sprintf("%d (failure rate %s%%)", report.FailedEvents, formatFloat(round(report.FailureRate*100)))})
+	table.Add([]string{"Instance-hours", formatFloat(report.InstanceHours)})
+	table.Add([]string{"Peak instances", strconv.Itoa(report.PeakInstances)})
+	table.Add([]string{"Average instances", formatFloat(report.AverageInstances)})
+	table.Add([]string{"Flapping pairs", fmt.Sprintf("%d (cool-down %ds)", report.FlappingPairs, report.CoolDownSeconds)})
+	table.Add([]string{fmt.Sprintf("Time at min instances (%d)", report.InstanceMin),
+		fmt.Sprintf("%s (%s)", time.Duration(report.SecondsAtMin)*time.Second, share(report.SecondsAtMin))})
+	table.Add([]string{fmt.Sprintf("Time at max instances (%d)", report.InstanceMax),
+		fmt.Sprintf("%s (%s)", time.Duration(report.SecondsAtMax)*time.Second, share(report.SecondsAtMax))})
+	table.Print()
+
+	if len(report.Metrics) == 0 {
+		return
+	}
+	fmt.Fprintln(writer)
+	metricsTable := ui.NewTable(writer, []string{"Metrics Name", "Count", "Mean", "P95", "Max"})
+	for _, metric := range report.Metrics {
+		metricsTable.Add([]string{metric.Name, strconv.Itoa(metric.Count),
+			formatFloat(metric.Mean) + metric.Unit, formatFloat(metric.P95) + metric.Unit, formatFloat(metric.Max) + metric.Unit})
+	}
+	metricsTable.Print()
+}
+
+// round rounds to 2 decimals
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
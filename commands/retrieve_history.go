@@ -1,24 +1,34 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
-	ctime "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
 )
 
 type HistoryCommand struct {
 	RequiredlArgs HistoryPositionalArgs `positional-args:"yes"`
-	StartTime     string                `long:"start" description:"start time of metrics collected with format \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\" or \"yyyy-MM-ddTHH:mm:ssZ\", default to very beginning if not specified."`
-	EndTime       string                `long:"end" description:"end time of the metrics collected with format \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\" or \"yyyy-MM-ddTHH:mm:ssZ\", default to current time if not speficied."`
+	Since         TimeOption            `long:"since" description:"show the history of the given duration before now, e.g. 30m, 2h or 7d, cannot be used with --start."`
+	StartTime     TimeOption            `long:"start" description:"start time of the history, default to very beginning if not specified. Supported formats are \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\", \"yyyy-MM-ddTHH:mm:ssZ\", \"yyyy-MM-ddTHH:mm:ss\" and \"yyyy-MM-dd\" in the local time zone, \"now\", a duration before now like \"-30m\" and Unix epoch seconds or milliseconds."`
+	EndTime       TimeOption            `long:"end" description:"end time of the history in the same formats as --start, default to current time if not specified."`
 	Desc          bool                  `long:"desc" description:"display in descending order, default to ascending order if not specified."`
 	Asc           bool                  `long:"asc" description:"display in ascending order, default to descending order if not specified."`
 	Output        string                `long:"output" description:"dump the policy to a file in JSON format"`
+	Format        string                `long:"format" choice:"table" choice:"json" choice:"jsonl" choice:"csv" default:"table" description:"output format of the scaling history, all pages are retrieved for json, jsonl and csv"`
+	Follow        bool                  `long:"follow" short:"f" description:"keep polling and print new scaling events until interrupted"`
+	Interval      time.Duration         `long:"interval" default:"10s" description:"poll interval of --follow"`
+	HistoryFilterOptions
+	TimeDisplayOptions
 }
 
 type HistoryPositionalArgs struct {
@@ -28,34 +38,38 @@ type HistoryPositionalArgs struct {
 func (command HistoryCommand) Execute([]string) error {
 
 	var (
-		st     int64 = 0
-		et     int64 = time.Now().UnixNano()
-		fpo    bool  = false
+		fpo    bool = false
 		err    error
 		writer *os.File
 	)
 	if command.Desc && command.Asc {
 		return fmt.Errorf(ui.DeprecatedDescWarning)
 	}
-	if command.StartTime != "" {
-		st, err = ctime.ParseTimeFormat(command.StartTime)
-		if err != nil {
-			return err
-		}
+	st, et, err := parseTimeRange(command.Since, command.StartTime, command.EndTime)
+	if err != nil {
+		return err
 	}
-	if command.EndTime != "" {
-		et, err = ctime.ParseTimeFormat(command.EndTime)
-		if err != nil {
-			return err
-		}
+	err = command.TimeDisplayOptions.validate()
+	if err != nil {
+		return err
 	}
-	if st > et {
-		return errors.New(fmt.Sprintf(ui.InvalidTimeRange, command.StartTime, command.EndTime))
+	// matching events may be on any page
+	fpo = command.Since == "" && command.StartTime == "" && command.EndTime == "" && !command.HistoryFilterOptions.active()
+
+	if command.Follow {
+		if command.EndTime != "" {
+			return errors.New(ui.FollowWithEndTime)
+		}
+		if command.Format != ui.FormatTable {
+			return errors.New(ui.FollowWithFormat)
+		}
+		if command.Interval <= 0 {
+			return fmt.Errorf(ui.InvalidInterval, command.Interval)
+		}
 	}
-	fpo = command.StartTime == "" && command.EndTime == ""
 
 	if command.Output != "" {
-		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY, 0666)
+		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 		if err != nil {
 			return err
 		}
@@ -64,14 +78,18 @@ func (command HistoryCommand) Execute([]string) error {
 		writer = os.Stdout
 	}
 
+	if command.Follow {
+		return FollowHistory(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, st, command.Interval, command.HistoryFilterOptions, command.TimeDisplayOptions, writer, command.Output)
+	}
+
 	return RetrieveHistory(AutoScaler.CLIConnection,
 		command.RequiredlArgs.AppName,
-		st, et, fpo, command.Desc, command.Asc, writer, command.Output)
+		st, et, fpo, command.Desc, command.Asc, command.Format, command.HistoryFilterOptions, command.TimeDisplayOptions, writer, command.Output)
 }
 
-func RetrieveHistory(cliConnection api.Connection, appName string, startTime, endTime int64, firstPageOnly bool, desc bool, asc bool, writer io.Writer, outputfile string) error {
+func RetrieveHistory(cliConnection api.Connection, appName string, startTime, endTime int64, firstPageOnly bool, desc bool, asc bool, format string, filter HistoryFilterOptions, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
 
-	cfclient, err := api.NewCFClient(cliConnection)
+	cfclient, err := newCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
@@ -89,6 +107,27 @@ func RetrieveHistory(cliConnection api.Connection, appName string, startTime, en
 	}
 
 	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if format != "" && format != ui.FormatTable {
+		if outputfile != "" {
+			ui.SayMessage(ui.SaveHistoryHint, appName, outputfile)
+		}
+		err = exportHistory(apihelper, startTime, endTime, asc, filter, timeOptions.exportTime(), writer, format)
+		if err != nil {
+			return err
+		}
+		if outputfile != "" {
+			ui.SayOK()
+		}
+		if desc {
+			ui.SayWarningMessageToStderr(ui.DeprecatedDescWarning)
+		}
+		return nil
+	}
 
 	if outputfile != "" {
 		ui.SayMessage(ui.SaveHistoryHint, appName, outputfile)
@@ -96,25 +135,26 @@ func RetrieveHistory(cliConnection api.Connection, appName string, startTime, en
 		ui.SayMessage(ui.ShowHistoryHint, appName)
 	}
 
-	table := ui.NewTable(writer, []string{"Scaling Type", "Status", "Instance Changes", "Time", "Action", "Error"})
+	table := ui.NewTable(writer, historyHeaders)
 	var (
 		page       uint64 = 1
 		next       bool   = true
 		noResult   bool   = true
 		moreResult bool   = false
-		data       [][]string
+		histories  []*models.AppScalingHistory
 	)
 
 	for {
-		next, data, err = apihelper.GetHistory(startTime, endTime, asc, page)
+		next, histories, err = apihelper.GetHistoryRecords(startTime, endTime, asc, page)
 		if err != nil {
 			return err
 		}
 
-		for _, row := range data {
-			table.Add(row)
+		histories = filter.filter(histories)
+		for _, history := range histories {
+			table.Add(apihelper.HistoryRow(history))
 		}
-		if len(data) > 0 {
+		if len(histories) > 0 {
 			noResult = false
 			table.Print()
 		}
@@ -126,7 +166,10 @@ func RetrieveHistory(cliConnection api.Connection, appName string, startTime, en
 		page += 1
 	}
 
-	if noResult {
+	if noResult && filter.active() {
+		ui.SayOK()
+		ui.SayMessage(ui.HistoryNotMatched, appName)
+	} else if noResult {
 		ui.SayOK()
 		ui.SayMessage(ui.HistoryNotFound, appName)
 	} else {
@@ -143,3 +186,151 @@ func RetrieveHistory(cliConnection api.Connection, appName string, startTime, en
 
 	return nil
 }
+
+var historyHeaders = []string{"Scaling Type", "Status", "Instance Changes", "Time", "Action", "Error"}
+
+var historyColumns = []string{"app_id", "timestamp", "scaling_type", "status", "old_instances", "new_instances", "reason", "message", "error"}
+
+// exportHistory writes the scaling events of all pages matching the filter,
+// withTime adds the timestamp formatted by the API helper as the time column.
+func exportHistory(apihelper *api.APIHelper, startTime, endTime int64, asc bool, filter HistoryFilterOptions, withTime bool, writer io.Writer, format string) error {
+
+	columns := historyColumns
+	if withTime {
+		columns = append(append([]string{}, historyColumns...), "time")
+	}
+	exporter := ui.NewExporter(writer, format, columns)
+
+	var page uint64 = 1
+	for {
+		next, histories, err := apihelper.GetHistoryRecords(startTime, endTime, asc, page)
+		if err != nil {
+			return err
+		}
+
+		for _, history := range filter.filter(histories) {
+			var record interface{} = history
+			row := []string{history.AppId, strconv.FormatInt(history.Timestamp, 10),
+				strconv.Itoa(int(history.ScalingType)), strconv.Itoa(int(history.Status)),
+				strconv.Itoa(history.OldInstances), strconv.Itoa(history.NewInstances),
+				history.Reason, history.Message, history.Error}
+			if withTime {
+				formatted := apihelper.TimeFormatter.Format(history.Timestamp)
+				record = struct {
+					*models.AppScalingHistory
+					Time string `json:"time"`
+				}{history, formatted}
+				row = append(row, formatted)
+			}
+			err = exporter.Add(record, row)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !next {
+			break
+		}
+		page += 1
+	}
+	return exporter.Flush()
+}
+
+// FollowHistory polls the scaling history until interrupted and prints new
+// events in ascending order. Without a start time only events from now on are
+// printed. Errors are reported and retried at the next poll.
+func FollowHistory(cliConnection api.Connection, appName string, startTime int64, interval time.Duration, filter HistoryFilterOptions, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveHistoryHint, appName, outputfile)
+	}
+	ui.SayMessage(ui.FollowHistoryHint, appName)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if startTime == 0 {
+		startTime = time.Now().UnixNano()
+	}
+	// the start time is inclusive, the event at the cursor is skipped when
+	// it is returned again
+	cursor := startTime - 1
+	table := ui.NewTable(writer, historyHeaders)
+	for {
+		cursor, err = pollHistory(apihelper, filter, table, cursor)
+		if err != nil {
+			ui.SayWarningMessage(ui.FollowHistoryRetry, interval, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			if outputfile != "" {
+				ui.SayOK()
+			}
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollHistory prints the events newer than the cursor matching the filter and
+// returns the timestamp of the last retrieved event. The pages of a poll are
+// all queried from the cursor as the page offsets refer to the same query.
+func pollHistory(apihelper *api.APIHelper, filter HistoryFilterOptions, table ui.Table, cursor int64) (int64, error) {
+
+	var (
+		page    uint64 = 1
+		endTime        = time.Now().UnixNano()
+		latest         = cursor
+	)
+	for {
+		next, histories, err := apihelper.GetHistoryRecords(cursor, endTime, true, page)
+		if err != nil {
+			return latest, err
+		}
+
+		printed := false
+		for _, history := range histories {
+			if history.Timestamp <= latest {
+				continue
+			}
+			latest = history.Timestamp
+			if !filter.matches(history) {
+				continue
+			}
+			table.Add(apihelper.HistoryRow(history))
+			printed = true
+		}
+		if printed {
+			table.Print()
+		}
+
+		if !next {
+			return latest, nil
+		}
+		page += 1
+	}
+}
@@ -1,66 +1,106 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
-	"regexp"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
-	ctime "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/util/stats"
 )
 
 type MetricsCommand struct {
 	RequiredlArgs MetricsPositionalArgs `positional-args:"yes"`
-	StartTime     string                `long:"start" description:"start time of metrics collected with format \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\" or \"yyyy-MM-ddTHH:mm:ssZ\", default to very beginning if not specified."`
-	EndTime       string                `long:"end" description:"end time of the metrics collected with format \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\" or \"yyyy-MM-ddTHH:mm:ssZ\", default to current time if not speficied."`
+	Since         TimeOption            `long:"since" description:"show the metrics of the given duration before now, e.g. 30m, 2h or 7d, cannot be used with --start."`
+	StartTime     TimeOption            `long:"start" description:"start time of the metrics, default to very beginning if not specified. Supported formats are \"yyyy-MM-ddTHH:mm:ss+/-HH:mm\", \"yyyy-MM-ddTHH:mm:ssZ\", \"yyyy-MM-ddTHH:mm:ss\" and \"yyyy-MM-dd\" in the local time zone, \"now\", a duration before now like \"-30m\" and Unix epoch seconds or milliseconds."`
+	EndTime       TimeOption            `long:"end" description:"end time of the metrics in the same formats as --start, default to current time if not specified."`
 	Desc          bool                  `long:"desc" description:"display in descending order, default to ascending order if not specified."`
 	Asc           bool                  `long:"asc" description:"display in ascending order, default to descending order if not specified."`
 	Output        string                `long:"output" description:"dump the policy to a file in JSON format"`
+	Format        string                `long:"format" choice:"table" choice:"json" choice:"jsonl" choice:"csv" default:"table" description:"output format of the metrics, all pages are retrieved for json, jsonl and csv"`
+	Watch         bool                  `long:"watch" short:"w" description:"keep polling and print new metrics until interrupted, annotated with the thresholds they cross"`
+	Interval      time.Duration         `long:"interval" default:"10s" description:"poll interval of --watch"`
+	Chart         string                `long:"chart" optional:"yes" optional-value:"line" choice:"line" choice:"sparkline" description:"draw the metrics as a line chart or, with --chart=sparkline, as a sparkline sized to the terminal width, with the thresholds of the policy as horizontal lines"`
+	Summary       bool                  `long:"summary" description:"print count, min, max, mean, median, p90, p95, p99 and standard deviation of all metrics in the time range"`
+	AllPolicy     bool                  `long:"all-policy-metrics" description:"retrieve every metric type of the scaling rules of the app's policy instead of METRIC_NAME"`
+	TimeDisplayOptions
 }
 
 type MetricsPositionalArgs struct {
-	AppName    string `positional-arg-name:"APP_NAME" required:"true"`
-	MetricName string `positional-arg-name:"METRIC_NAME" required:"true" description:"available metric for the application:\n memoryused, memoryutil, responsetime, throughput, cpu or custom metrics"`
+	AppName    string `positional-arg-name:"APP_NAME"`
+	MetricName string `positional-arg-name:"METRIC_NAME" description:"available metric for the application:\n memoryused, memoryutil, responsetime, throughput, cpu or custom metrics, or a comma-separated list of them"`
 }
 
 func (command MetricsCommand) Execute([]string) error {
-	if ok, _ := regexp.MatchString("^[a-zA-Z0-9_]+$", command.RequiredlArgs.MetricName); !ok {
-		return errors.New(fmt.Sprintf(ui.UnrecognizedMetricName, command.RequiredlArgs.MetricName))
+	// the arguments are checked here as METRIC_NAME is optional with
+	// --all-policy-metrics
+	if command.RequiredlArgs.AppName == "" {
+		if command.AllPolicy {
+			return errors.New(ui.AppNameRequired)
+		}
+		return errors.New(ui.MetricArgsRequired)
+	}
+	metricNames, err := parseMetricNames(command.RequiredlArgs.MetricName, command.AllPolicy)
+	if err != nil {
+		return err
 	}
+	multiple := command.AllPolicy || len(metricNames) > 1
 
 	var (
-		st     int64 = 0
-		et     int64 = time.Now().UnixNano()
-		fpo    bool  = false
-		err    error
+		fpo    bool = false
 		writer *os.File
 	)
 	if command.Desc && command.Asc {
 		return fmt.Errorf(ui.DeprecatedDescWarning)
 	}
-	if command.StartTime != "" {
-		st, err = ctime.ParseTimeFormat(command.StartTime)
-		if err != nil {
-			return err
-		}
+	st, et, err := parseTimeRange(command.Since, command.StartTime, command.EndTime)
+	if err != nil {
+		return err
 	}
-	if command.EndTime != "" {
-		et, err = ctime.ParseTimeFormat(command.EndTime)
-		if err != nil {
-			return err
+	err = command.TimeDisplayOptions.validate()
+	if err != nil {
+		return err
+	}
+	fpo = command.Since == "" && command.StartTime == "" && command.EndTime == ""
+
+	if command.Summary && command.Watch {
+		return errors.New(ui.SummaryWithWatch)
+	}
+	if command.Chart != "" {
+		if command.Watch || command.Summary {
+			return errors.New(ui.ChartWithWatch)
 		}
+		if command.Format != ui.FormatTable {
+			return errors.New(ui.ChartWithFormat)
+		}
+	}
+	if multiple && (command.Watch || command.Chart != "") {
+		return errors.New(ui.MultipleMetrics)
 	}
-	if st > et {
-		return errors.New(fmt.Sprintf(ui.InvalidTimeRange, command.StartTime, command.EndTime))
+	if command.Watch {
+		if command.EndTime != "" {
+			return errors.New(ui.WatchWithEndTime)
+		}
+		if command.Format != ui.FormatTable {
+			return errors.New(ui.WatchWithFormat)
+		}
+		if command.Interval <= 0 {
+			return fmt.Errorf(ui.InvalidInterval, command.Interval)
+		}
 	}
-	fpo = command.StartTime == "" && command.EndTime == ""
 
 	if command.Output != "" {
-		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY, 0666)
+		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 		if err != nil {
 			return err
 		}
@@ -68,14 +108,39 @@ func (command MetricsCommand) Execute([]string) error {
 	} else {
 		writer = os.Stdout
 	}
+
+	if command.Watch {
+		return WatchAggregatedMetrics(AutoScaler.CLIConnection,
+			command.RequiredlArgs.AppName, command.RequiredlArgs.MetricName,
+			st, command.Interval, command.TimeDisplayOptions, writer, command.Output)
+	}
+
+	if command.Chart != "" {
+		return ChartAggregatedMetrics(AutoScaler.CLIConnection,
+			command.RequiredlArgs.AppName, command.RequiredlArgs.MetricName,
+			st, et, command.Chart, command.TimeDisplayOptions, writer, command.Output)
+	}
+
+	if command.Summary {
+		return SummarizeAggregatedMetrics(AutoScaler.CLIConnection,
+			command.RequiredlArgs.AppName, metricNames, command.AllPolicy,
+			st, et, command.Format, writer, command.Output)
+	}
+
+	if multiple {
+		return RetrieveMultipleAggregatedMetrics(AutoScaler.CLIConnection,
+			command.RequiredlArgs.AppName, metricNames, command.AllPolicy,
+			st, et, fpo, command.Desc, command.Asc, command.Format, command.TimeDisplayOptions, writer, command.Output)
+	}
+
 	return RetrieveAggregatedMetrics(AutoScaler.CLIConnection,
 		command.RequiredlArgs.AppName, command.RequiredlArgs.MetricName,
-		st, et, fpo, command.Desc, command.Asc, writer, command.Output)
+		st, et, fpo, command.Desc, command.Asc, command.Format, command.TimeDisplayOptions, writer, command.Output)
 }
 
-func RetrieveAggregatedMetrics(cliConnection api.Connection, appName, metricName string, startTime, endTime int64, firstPageOnly bool, desc bool, asc bool, writer io.Writer, outputfile string) error {
+func RetrieveAggregatedMetrics(cliConnection api.Connection, appName, metricName string, startTime, endTime int64, firstPageOnly bool, desc bool, asc bool, format string, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
 
-	cfclient, err := api.NewCFClient(cliConnection)
+	cfclient, err := newCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
@@ -94,6 +159,27 @@ func RetrieveAggregatedMetrics(cliConnection api.Connection, appName, metricName
 	}
 
 	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if format != "" && format != ui.FormatTable {
+		if outputfile != "" {
+			ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
+		}
+		err = exportAggregatedMetrics(apihelper, metricName, startTime, endTime, asc, timeOptions.exportTime(), writer, format)
+		if err != nil {
+			return err
+		}
+		if outputfile != "" {
+			ui.SayOK()
+		}
+		if desc {
+			ui.SayWarningMessageToStderr(ui.DeprecatedDescWarning)
+		}
+		return nil
+	}
 
 	if outputfile != "" {
 		ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
@@ -149,3 +235,414 @@ func RetrieveAggregatedMetrics(cliConnection api.Connection, appName, metricName
 
 	return nil
 }
+
+var metricColumns = []string{"app_id", "name", "unit", "value", "timestamp"}
+
+// exportAggregatedMetrics writes all pages of the metrics, withTime adds the
+// timestamp formatted by the API helper as the time column.
+func exportAggregatedMetrics(apihelper *api.APIHelper, metricName string, startTime, endTime int64, asc bool, withTime bool, writer io.Writer, format string) error {
+
+	columns := metricColumns
+	if withTime {
+		columns = append(append([]string{}, metricColumns...), "time")
+	}
+	exporter := ui.NewExporter(writer, format, columns)
+
+	var page uint64 = 1
+	for {
+		next, metrics, err := apihelper.GetAggregatedMetricRecords(metricName, startTime, endTime, asc, page)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range metrics {
+			var record interface{} = metric
+			row := []string{metric.AppId, metric.Name, metric.Unit, metric.Value,
+				strconv.FormatInt(metric.Timestamp, 10)}
+			if withTime {
+				formatted := apihelper.TimeFormatter.Format(metric.Timestamp)
+				record = struct {
+					*models.AppAggregatedMetric
+					Time string `json:"time"`
+				}{metric, formatted}
+				row = append(row, formatted)
+			}
+			err = exporter.Add(record, row)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !next {
+			break
+		}
+		page += 1
+	}
+	return exporter.Flush()
+}
+
+// WatchAggregatedMetrics polls the aggregated metrics until interrupted and
+// prints new data points in ascending order. Each data point is annotated with
+// the scaling rules of the current policy whose threshold it crosses. Without
+// a start time only metrics from now on are printed. Errors are reported and
+// retried at the next poll.
+func WatchAggregatedMetrics(cliConnection api.Connection, appName, metricName string, startTime int64, interval time.Duration, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
+	}
+	ui.SayMessage(ui.WatchAggregatedMetricHint, metricName, appName)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if startTime == 0 {
+		startTime = time.Now().UnixNano()
+	}
+	// the start time is inclusive, the metric at the cursor is skipped when
+	// it is returned again
+	cursor := startTime - 1
+	table := ui.NewTable(writer, []string{"Metrics Name", "Value", "Timestamp", "Threshold Crossed"})
+	for {
+		cursor, err = pollAggregatedMetrics(apihelper, table, metricName, cursor)
+		if err != nil {
+			ui.SayWarningMessage(ui.WatchMetricsRetry, interval, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			if outputfile != "" {
+				ui.SayOK()
+			}
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollAggregatedMetrics prints the metrics newer than the cursor and returns
+// the timestamp of the last printed metric. The policy is fetched on every
+// poll so that the annotations follow policy changes, the pages of a poll are
+// all queried from the cursor as the page offsets refer to the same query.
+func pollAggregatedMetrics(apihelper *api.APIHelper, table ui.Table, metricName string, cursor int64) (int64, error) {
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return cursor, err
+	}
+	rules := metricRules(policy, metricName)
+
+	var (
+		page    uint64 = 1
+		endTime        = time.Now().UnixNano()
+		latest         = cursor
+	)
+	for {
+		next, metrics, err := apihelper.GetAggregatedMetricRecords(metricName, cursor, endTime, true, page)
+		if err != nil {
+			return latest, err
+		}
+
+		printed := false
+		for _, metric := range metrics {
+			if metric.Timestamp <= latest {
+				continue
+			}
+			table.Add(append(apihelper.MetricRow(metric), crossedThresholds(rules, metric.Value)))
+			latest = metric.Timestamp
+			printed = true
+		}
+		if printed {
+			table.Print()
+		}
+
+		if !next {
+			return latest, nil
+		}
+		page += 1
+	}
+}
+
+// crossedThresholds lists the rules whose condition the value meets, e.g.
+// ">= 80 (+1)", or returns an empty string if there are none.
+func crossedThresholds(rules []*models.ScalingRule, value string) string {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return ""
+	}
+
+	var crossed []string
+	for _, rule := range rules {
+		threshold := float64(rule.Threshold)
+		var breached bool
+		switch rule.Operator {
+		case "<":
+			breached = v < threshold
+		case "<=":
+			breached = v <= threshold
+		case ">":
+			breached = v > threshold
+		case ">=":
+			breached = v >= threshold
+		}
+		if breached {
+			crossed = append(crossed, fmt.Sprintf("%s %d (%s)", rule.Operator, rule.Threshold, rule.Adjustment))
+		}
+	}
+	return strings.Join(crossed, ", ")
+}
+
+// metricSeries are the values of one metric name in order of retrieval
+type metricSeries struct {
+	name   string
+	unit   string
+	values []float64
+}
+
+type metricSummary struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+	stats.Summary
+}
+
+var summaryColumns = []string{"name", "unit", "count", "min", "max", "mean", "median", "p90", "p95", "p99", "stddev"}
+
+// SummarizeAggregatedMetrics retrieves all pages of the metrics in the time
+// range concurrently and prints their statistics per metric name, the values
+// keep the unit of the metrics.
+func SummarizeAggregatedMetrics(cliConnection api.Connection, appName string, metricNames []string, allPolicyMetrics bool, startTime, endTime int64, format string, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	metricNames, err = resolveMetricNames(apihelper, appName, metricNames, allPolicyMetrics)
+	if err != nil {
+		return err
+	}
+	metricName := strings.Join(metricNames, ", ")
+
+	table := format == "" || format == ui.FormatTable
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
+	} else if table {
+		ui.SayMessage(ui.SummarizeAggregatedMetricsHint, metricName, appName)
+	}
+
+	series, err := collectMetricSeries(apihelper, metricNames, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	if !table {
+		exporter := ui.NewExporter(writer, format, summaryColumns)
+		for _, s := range series {
+			summary := metricSummary{Name: s.name, Unit: s.unit, Summary: stats.Summarize(s.values)}
+			err = exporter.Add(summary, append([]string{summary.Name, summary.Unit, strconv.Itoa(summary.Count)},
+				formatStatistics(summary.Summary, "")...))
+			if err != nil {
+				return err
+			}
+		}
+		err = exporter.Flush()
+		if err != nil {
+			return err
+		}
+		if outputfile != "" {
+			ui.SayOK()
+		}
+		return nil
+	}
+
+	if len(series) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.AggregatedMetricsNotFound, metricName, appName)
+		return nil
+	}
+
+	summaryTable := ui.NewTable(writer, []string{"Metrics Name", "Count", "Min", "Max", "Mean", "Median", "P90", "P95", "P99", "Std Dev"})
+	for _, s := range series {
+		summary := stats.Summarize(s.values)
+		summaryTable.Add(append([]string{s.name, strconv.Itoa(summary.Count)}, formatStatistics(summary, s.unit)...))
+	}
+	summaryTable.Print()
+	if outputfile != "" {
+		ui.SayOK()
+	}
+	return nil
+}
+
+// collectMetricSeries groups the metrics by name, names without metrics are
+// left out
+func collectMetricSeries(apihelper *api.APIHelper, metricNames []string, startTime, endTime int64) ([]*metricSeries, error) {
+
+	fetched, _, err := fetchMetrics(apihelper, metricNames, startTime, endTime, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []*metricSeries
+	for _, metrics := range fetched {
+		byName := map[string]*metricSeries{}
+		for _, metric := range metrics {
+			value, err := strconv.ParseFloat(metric.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf(ui.InvalidMetricValue, metric.Value, metric.Name)
+			}
+			s, ok := byName[metric.Name]
+			if !ok {
+				s = &metricSeries{name: metric.Name, unit: metric.Unit}
+				byName[metric.Name] = s
+				series = append(series, s)
+			}
+			s.values = append(s.values, value)
+		}
+	}
+	return series, nil
+}
+
+// formatStatistics formats min, max, mean, median, percentiles and standard
+// deviation rounded to 2 decimals.
+func formatStatistics(summary stats.Summary, unit string) []string {
+	values := []float64{summary.Min, summary.Max, summary.Mean, summary.Median, summary.P90, summary.P95, summary.P99, summary.StdDev}
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64) + unit
+	}
+	return formatted
+}
+
+// metricRules returns the scaling rules of the policy for the metric type
+func metricRules(policy *models.ScalingPolicy, metricName string) []*models.ScalingRule {
+	var rules []*models.ScalingRule
+	if policy != nil {
+		for _, rule := range policy.ScalingRules {
+			if rule != nil && rule.MetricType == metricName {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// ChartAggregatedMetrics retrieves all pages of the metrics in the time range
+// and draws them as a chart on the terminal together with the thresholds of
+// the scaling rules for the metric. With an output file the plain table is
+// written to the file as well.
+func ChartAggregatedMetrics(cliConnection api.Connection, appName, metricName string, startTime, endTime int64, style string, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
+	}
+	ui.SayMessage(ui.ShowAggregatedMetricsHint, metricName, appName)
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return err
+	}
+
+	var (
+		page    uint64 = 1
+		metrics []*models.AppAggregatedMetric
+	)
+	for {
+		next, records, err := apihelper.GetAggregatedMetricRecords(metricName, startTime, endTime, true, page)
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, records...)
+		if !next {
+			break
+		}
+		page += 1
+	}
+
+	if len(metrics) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.AggregatedMetricsNotFound, metricName, appName)
+		return nil
+	}
+
+	first, last := metrics[0], metrics[len(metrics)-1]
+	chart := ui.NewChart(os.Stdout, style, ui.TerminalWidth(), first.Unit,
+		apihelper.TimeFormatter.Format(first.Timestamp), apihelper.TimeFormatter.Format(last.Timestamp))
+	table := ui.NewTable(writer, []string{"Metrics Name", "Value", "Timestamp"})
+	for _, metric := range metrics {
+		value, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			return fmt.Errorf(ui.InvalidMetricValue, metric.Value, metric.Name)
+		}
+		chart.Add(value)
+		table.Add(apihelper.MetricRow(metric))
+	}
+	for _, rule := range metricRules(policy, metricName) {
+		chart.AddThreshold(float64(rule.Threshold), fmt.Sprintf("%s %s %d%s (%s)", rule.MetricType, rule.Operator, rule.Threshold, first.Unit, rule.Adjustment))
+	}
+
+	chart.Print()
+	if outputfile != "" {
+		table.Print()
+		ui.SayOK()
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+var metricNamePattern = regexp.MustCompile("^[a-zA-Z0-9_]+$")
+
+// parseMetricNames splits the comma-separated METRIC_NAME, the names are
+// looked up in the policy later on with --all-policy-metrics.
+func parseMetricNames(metricName string, allPolicyMetrics bool) ([]string, error) {
+	if allPolicyMetrics {
+		if metricName != "" {
+			return nil, errors.New(ui.AllPolicyWithMetric)
+		}
+		return nil, nil
+	}
+	if metricName == "" {
+		return nil, errors.New(ui.MetricNameRequired)
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(metricName, ",") {
+		name = strings.TrimSpace(name)
+		if !metricNamePattern.MatchString(name) {
+			return nil, fmt.Errorf(ui.UnrecognizedMetricName, name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// resolveMetricNames returns the metric types of the scaling rules in order of
+// appearance with --all-policy-metrics, the given names otherwise.
+func resolveMetricNames(apihelper *api.APIHelper, appName string, metricNames []string, allPolicyMetrics bool) ([]string, error) {
+	if !allPolicyMetrics {
+		return metricNames, nil
+	}
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf(ui.PolicyNotFound, appName)
+	}
+	names := policyMetricNames(policy)
+	if len(names) == 0 {
+		return nil, fmt.Errorf(ui.NoPolicyMetrics, appName)
+	}
+	return names, nil
+}
+
+// policyMetricNames lists the metric types of the scaling rules without
+// duplicates in order of appearance
+func policyMetricNames(policy *models.ScalingPolicy) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, rule := range policy.ScalingRules {
+		if rule != nil && !seen[rule.MetricType] {
+			seen[rule.MetricType] = true
+			names = append(names, rule.MetricType)
+		}
+	}
+	return names
+}
+
+// fetchMetrics retrieves the metrics of every name concurrently, the result
+// is in the order of the names. more tells whether a first page was not the
+// last one.
+func fetchMetrics(apihelper *api.APIHelper, metricNames []string, startTime, endTime int64, asc bool, firstPageOnly bool) ([][]*models.AppAggregatedMetric, bool, error) {
+
+	var (
+		series = make([][]*models.AppAggregatedMetric, len(metricNames))
+		more   = make([]bool, len(metricNames))
+		errs   = make([]error, len(metricNames))
+		wg     sync.WaitGroup
+	)
+	for i, metricName := range metricNames {
+		wg.Add(1)
+		go func(i int, metricName string) {
+			defer wg.Done()
+
+			var page uint64 = 1
+			for {
+				next, metrics, err := apihelper.GetAggregatedMetricRecords(metricName, startTime, endTime, asc, page)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				series[i] = append(series[i], metrics...)
+				if !next || firstPageOnly {
+					more[i] = next
+					return
+				}
+				page += 1
+			}
+		}(i, metricName)
+	}
+	wg.Wait()
+
+	moreResult := false
+	for i := range metricNames {
+		if errs[i] != nil {
+			return nil, false, errs[i]
+		}
+		moreResult = moreResult || more[i]
+	}
+	return series, moreResult, nil
+}
+
+// alignedMetrics are the values of several metrics at one timestamp, a name
+// without a value at the timestamp is missing from Metrics
+type alignedMetrics struct {
+	Timestamp int64                    `json:"timestamp"`
+	Time      string                   `json:"time,omitempty"`
+	Metrics   map[string]*alignedValue `json:"metrics"`
+}
+
+type alignedValue struct {
+	Value string `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+// alignMetrics joins the series by timestamp in ascending or descending order
+func alignMetrics(metricNames []string, series [][]*models.AppAggregatedMetric, asc bool) []*alignedMetrics {
+	byTimestamp := map[int64]*alignedMetrics{}
+	var rows []*alignedMetrics
+	for i, metrics := range series {
+		for _, metric := range metrics {
+			row, ok := byTimestamp[metric.Timestamp]
+			if !ok {
+				row = &alignedMetrics{Timestamp: metric.Timestamp, Metrics: map[string]*alignedValue{}}
+				byTimestamp[metric.Timestamp] = row
+				rows = append(rows, row)
+			}
+			row.Metrics[metricNames[i]] = &alignedValue{Value: metric.Value, Unit: metric.Unit}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if asc {
+			return rows[i].Timestamp < rows[j].Timestamp
+		}
+		return rows[i].Timestamp > rows[j].Timestamp
+	})
+	return rows
+}
+
+// RetrieveMultipleAggregatedMetrics prints several metrics aligned by
+// timestamp, one column per metric.
+func RetrieveMultipleAggregatedMetrics(cliConnection api.Connection, appName string, metricNames []string, allPolicyMetrics bool, startTime, endTime int64, firstPageOnly bool, desc bool, asc bool, format string, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+	metricNames, err = resolveMetricNames(apihelper, appName, metricNames, allPolicyMetrics)
+	if err != nil {
+		return err
+	}
+
+	table := format == "" || format == ui.FormatTable
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveAggregatedMetricHint, appName, outputfile)
+	} else if table {
+		ui.SayMessage(ui.ShowAggregatedMetricsHint, strings.Join(metricNames, ", "), appName)
+	}
+
+	// all pages are exported, like for a single metric
+	series, moreResult, err := fetchMetrics(apihelper, metricNames, startTime, endTime, asc, firstPageOnly && table)
+	if err != nil {
+		return err
+	}
+	rows := alignMetrics(metricNames, series, asc)
+
+	if !table {
+		columns := append([]string{"timestamp"}, metricNames...)
+		if timeOptions.exportTime() {
+			columns = append(columns, "time")
+		}
+		exporter := ui.NewExporter(writer, format, columns)
+		for _, row := range rows {
+			cells := multipleMetricsRow(metricNames, row, strconv.FormatInt(row.Timestamp, 10), false)
+			if timeOptions.exportTime() {
+				row.Time = apihelper.TimeFormatter.Format(row.Timestamp)
+				cells = append(cells, row.Time)
+			}
+			err = exporter.Add(row, cells)
+			if err != nil {
+				return err
+			}
+		}
+		err = exporter.Flush()
+		if err != nil {
+			return err
+		}
+		if outputfile != "" {
+			ui.SayOK()
+		}
+		return nil
+	}
+
+	if len(rows) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.AggregatedMetricsNotFound, strings.Join(metricNames, ", "), appName)
+	} else {
+		metricsTable := ui.NewTable(writer, append([]string{"Timestamp"}, metricNames...))
+		for _, row := range rows {
+			metricsTable.Add(multipleMetricsRow(metricNames, row, apihelper.TimeFormatter.Format(row.Timestamp), true))
+		}
+		metricsTable.Print()
+		if outputfile != "" {
+			ui.SayOK()
+		}
+	}
+	if moreResult {
+		ui.SayWarningMessage(ui.MoreRecordsWarning)
+	}
+	if desc {
+		ui.SayWarningMessage(ui.DeprecatedDescWarning)
+	}
+	return nil
+}
+
+// multipleMetricsRow lists the values of the metrics after the timestamp, a
+// missing value is "-" in the table and empty in exports
+func multipleMetricsRow(metricNames []string, row *alignedMetrics, timestamp string, withUnit bool) []string {
+	cells := []string{timestamp}
+	for _, name := range metricNames {
+		value, ok := row.Metrics[name]
+		switch {
+		case !ok && withUnit:
+			cells = append(cells, "-")
+		case !ok:
+			cells = append(cells, "")
+		case withUnit:
+			cells = append(cells, value.Value+value.Unit)
+		default:
+			cells = append(cells, value.Value)
+		}
+	}
+	return cells
+}
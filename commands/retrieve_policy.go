@@ -1,18 +1,23 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+	"go.yaml.in/yaml/v3"
 )
 
 type PolicyCommand struct {
 	RequiredlArgs PolicyPositionalArgs `positional-args:"yes"`
-	Output        string               `long:"output" description:"dump the policy to a file in JSON format"`
+	Output        string               `long:"output" description:"dump the policy to a file in the chosen format"`
+	Format        string               `long:"format" choice:"json" choice:"yaml" default:"json" description:"output format of the policy"`
 }
 
 type PolicyPositionalArgs struct {
@@ -27,7 +32,7 @@ func (command PolicyCommand) Execute([]string) error {
 	)
 
 	if command.Output != "" {
-		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY, 0666)
+		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 		if err != nil {
 			return err
 		}
@@ -36,12 +41,12 @@ func (command PolicyCommand) Execute([]string) error {
 		writer = os.Stdout
 	}
 
-	return RetrievePolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, writer, command.Output)
+	return RetrievePolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.Format, writer, command.Output)
 }
 
-func RetrievePolicy(cliConnection api.Connection, appName string, writer io.Writer, outputfile string) error {
+func RetrievePolicy(cliConnection api.Connection, appName string, format string, writer io.Writer, outputfile string) error {
 
-	cfclient, err := api.NewCFClient(cliConnection)
+	cfclient, err := newCFClient(cliConnection)
 	if err != nil {
 		return err
 	}
@@ -71,6 +76,12 @@ func RetrievePolicy(cliConnection api.Connection, appName string, writer io.Writ
 	if err != nil {
 		return err
 	}
+	if format == ui.FormatYAML {
+		policy, err = policyToYAML(policy)
+		if err != nil {
+			return err
+		}
+	}
 	fmt.Fprintf(writer, "%v", string(policy))
 
 	if outputfile != "" {
@@ -78,3 +89,26 @@ func RetrievePolicy(cliConnection api.Connection, appName string, writer io.Writ
 	}
 	return nil
 }
+
+// policyToYAML converts the policy through models.ScalingPolicy, so that the
+// YAML field names match the JSON schema.
+func policyToYAML(policy []byte) ([]byte, error) {
+	var scalingPolicy models.ScalingPolicy
+	err := json.Unmarshal(policy, &scalingPolicy)
+	if err != nil {
+		return nil, fmt.Errorf(ui.InvalidPolicy, err)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&scalingPolicy)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
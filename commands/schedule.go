@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type ScheduleCommand struct {
+	RequiredlArgs SchedulePositionalArgs `positional-args:"yes"`
+	Next          int                    `long:"next" default:"10" description:"number of upcoming schedule windows to show"`
+	From          string                 `long:"from" description:"read the schedules from a policy file instead of the policy attached to the app, APP_NAME is optional then"`
+}
+
+type SchedulePositionalArgs struct {
+	AppName string `positional-arg-name:"APP_NAME"`
+}
+
+func (command ScheduleCommand) Execute([]string) error {
+	// the arguments are checked here as APP_NAME is optional with --from
+	if command.From == "" && command.RequiredlArgs.AppName == "" {
+		return errors.New(ui.AppNameRequired)
+	}
+	if command.Next <= 0 {
+		return fmt.Errorf(ui.InvalidScheduleCount, command.Next)
+	}
+	return PreviewSchedule(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.Next, command.From, time.Now(), os.Stdout)
+}
+
+// scheduleTimeLayout shows the time zone next to each time as the windows
+// are printed in two time zones
+const scheduleTimeLayout = "2006-01-02 15:04 MST"
+
+var scheduleHeaders = []string{"Schedule", "Start (Policy)", "End (Policy)", "Start (Local)", "End (Local)", "Min", "Max", "Initial", "Notes"}
+
+// PreviewSchedule prints the next windows of the schedules of the policy
+// attached to the app, or of the policy file if one is given.
+func PreviewSchedule(cliConnection api.Connection, appName string, next int, policyFile string, now time.Time, writer io.Writer) error {
+
+	var policy *models.ScalingPolicy
+	if policyFile != "" {
+		_, filePolicy, err := loadPolicyFile(policyFile)
+		if err != nil {
+			return err
+		}
+		ui.SayMessage(ui.ShowScheduleFileHint, policyFile)
+		policy = filePolicy
+	} else {
+		cfclient, err := newCFClient(cliConnection)
+		if err != nil {
+			return err
+		}
+
+		endpoint, err := api.GetEndpoint(cfclient)
+		if err != nil {
+			return err
+		}
+		if endpoint.URL == "" {
+			return errors.New(ui.NoEndpoint)
+		}
+		err = cfclient.Configure(appName)
+		if err != nil {
+			return err
+		}
+
+		apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+		ui.SayMessage(ui.ShowScheduleHint, appName)
+
+		policy, err = apihelper.GetScalingPolicy()
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			return fmt.Errorf(ui.PolicyNotFound, appName)
+		}
+	}
+
+	windows, err := policy.UpcomingScheduleWindows(now, next)
+	if err != nil {
+		return err
+	}
+	if len(windows) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.NoUpcomingSchedules)
+		return nil
+	}
+
+	ui.SayMessage(ui.ScheduleTimezone, policy.Schedules.Timezone, policy.InstanceMin, policy.InstanceMax)
+	table := ui.NewTable(writer, scheduleHeaders)
+	for _, window := range windows {
+		var notes []string
+		if len(window.Overlaps) > 0 {
+			notes = append(notes, "overlaps "+strings.Join(window.Overlaps, ", "))
+		}
+		if window.ShortGap() {
+			notes = append(notes, "short gap of "+window.Gap.String()+" to the next window")
+		} else if window.Gap > 0 {
+			notes = append(notes, "gap of "+window.Gap.String()+" to the next window")
+		}
+		table.Add([]string{
+			window.Schedule,
+			window.Start.Format(scheduleTimeLayout),
+			window.End.Format(scheduleTimeLayout),
+			window.Start.Local().Format(scheduleTimeLayout),
+			window.End.Local().Format(scheduleTimeLayout),
+			fmt.Sprint(window.InstanceMin),
+			fmt.Sprint(window.InstanceMax),
+			fmt.Sprint(window.InstanceInit),
+			strings.Join(notes, "; "),
+		})
+	}
+	table.Print()
+	return nil
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type SimulatePolicyCommand struct {
+	RequiredlArgs SimulatePolicyPositionalArgs `positional-args:"yes"`
+	Since         TimeOption                   `long:"since" description:"simulate the given duration before now, e.g. 30m, 2h or 7d, cannot be used with --start, default to 7d if neither is specified."`
+	StartTime     TimeOption                   `long:"start" description:"start time of the simulation in the same formats as autoscaling-history."`
+	EndTime       TimeOption                   `long:"end" description:"end time of the simulation in the same formats as --start, default to current time if not specified."`
+	Format        string                       `long:"format" choice:"table" choice:"json" default:"table" description:"output format of the simulation"`
+	Output        string                       `long:"output" description:"dump the simulation to a file in the chosen format"`
+	TimeDisplayOptions
+}
+
+type SimulatePolicyPositionalArgs struct {
+	AppName    string `positional-arg-name:"APP_NAME" required:"true"`
+	PolicyFile string `positional-arg-name:"PATH_TO_POLICY_FILE" required:"true"`
+}
+
+func (command SimulatePolicyCommand) Execute([]string) error {
+
+	var (
+		err    error
+		writer *os.File
+	)
+	since := command.Since
+	if since == "" && command.StartTime == "" {
+		since = defaultAnalysisRange
+	}
+	st, et, err := parseTimeRange(since, command.StartTime, command.EndTime)
+	if err != nil {
+		return err
+	}
+	err = command.TimeDisplayOptions.validate()
+	if err != nil {
+		return err
+	}
+
+	if command.Output != "" {
+		writer, err = os.OpenFile(command.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+	} else {
+		writer = os.Stdout
+	}
+
+	return SimulatePolicy(AutoScaler.CLIConnection, command.RequiredlArgs.AppName, command.RequiredlArgs.PolicyFile,
+		st, et, command.Format, command.TimeDisplayOptions, writer, command.Output)
+}
+
+// policySimulation puts the simulated scaling decisions next to the scaling
+// events that actually happened
+type policySimulation struct {
+	Simulated []*models.SimulatedScaling  `json:"simulated"`
+	Actual    []*models.AppScalingHistory `json:"actual"`
+}
+
+var simulationHeaders = []string{"Time", "Source", "Scaling Type", "Instance Changes", "Action"}
+
+// SimulatePolicy replays the aggregated metrics of the app in the time range
+// through the scaling rules and schedules of the policy file and prints the
+// scaling decisions next to the scaling history of the app.
+func SimulatePolicy(cliConnection api.Connection, appName string, policyFile string, startTime, endTime int64, format string, timeOptions TimeDisplayOptions, writer io.Writer, outputfile string) error {
+
+	_, policy, err := loadPolicyFile(policyFile)
+	if err != nil {
+		return err
+	}
+
+	cfclient, err := newCFClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := api.GetEndpoint(cfclient)
+	if err != nil {
+		return err
+	}
+	if endpoint.URL == "" {
+		return errors.New(ui.NoEndpoint)
+	}
+	err = cfclient.Configure(appName)
+	if err != nil {
+		return err
+	}
+
+	apihelper := api.NewAPIHelper(endpoint, cfclient, os.Getenv("CF_TRACE"))
+	apihelper.TimeFormatter, err = timeOptions.formatter(apihelper, appName)
+	if err != nil {
+		return err
+	}
+
+	table := format == "" || format == ui.FormatTable
+	if outputfile != "" {
+		ui.SayMessage(ui.SaveSimulationHint, appName, outputfile)
+	} else if table {
+		ui.SayMessage(ui.SimulatePolicyHint, policyFile, appName,
+			apihelper.TimeFormatter.Format(startTime), apihelper.TimeFormatter.Format(endTime))
+	}
+
+	simulation := &policySimulation{
+		Simulated: []*models.SimulatedScaling{},
+		Actual:    []*models.AppScalingHistory{},
+	}
+	var page uint64 = 1
+	for {
+		next, histories, err := apihelper.GetHistoryRecords(startTime, endTime, true, page)
+		if err != nil {
+			return err
+		}
+		simulation.Actual = append(simulation.Actual, histories...)
+		if !next {
+			break
+		}
+		page += 1
+	}
+
+	instances, err := initialInstances(apihelper, cfclient, startTime, simulation.Actual)
+	if err != nil {
+		return err
+	}
+
+	series, _, err := fetchMetrics(apihelper, policyMetricNames(policy), startTime, endTime, true, false)
+	if err != nil {
+		return err
+	}
+	var metrics []*models.AppAggregatedMetric
+	for _, s := range series {
+		metrics = append(metrics, s...)
+	}
+
+	decisions, err := policy.Simulate(metrics, instances, startTime, endTime)
+	if err != nil {
+		return err
+	}
+	simulation.Simulated = append(simulation.Simulated, decisions...)
+
+	if !table {
+		content, err := json.MarshalIndent(simulation, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(writer, string(content))
+		if outputfile != "" {
+			ui.SayOK()
+		}
+		return nil
+	}
+
+	if len(simulation.Simulated) == 0 && len(simulation.Actual) == 0 {
+		ui.SayOK()
+		ui.SayMessage(ui.NoScalingSimulated, appName)
+		return nil
+	}
+	printPolicySimulation(writer, simulation, apihelper)
+	if outputfile != "" {
+		ui.SayOK()
+	}
+
+	simulatedOut, simulatedIn := 0, 0
+	for _, decision := range simulation.Simulated {
+		if decision.NewInstances > decision.OldInstances {
+			simulatedOut++
+		} else {
+			simulatedIn++
+		}
+	}
+	actualOut, actualIn := 0, 0
+	for _, history := range simulation.Actual {
+		if history.Status != models.ScalingStatusSucceeded {
+			continue
+		}
+		switch scalingDirection(history) {
+		case 1:
+			actualOut++
+		case -1:
+			actualIn++
+		}
+	}
+	ui.SayMessage(ui.SimulationSummary, simulatedOut, simulatedIn, appName, actualOut, actualIn)
+	return nil
+}
+
+// printPolicySimulation prints the simulated and the actual scaling in
+// ascending order, a simulated decision comes first at the same time
+func printPolicySimulation(writer io.Writer, simulation *policySimulation, apihelper *api.APIHelper) {
+	type simulationRow struct {
+		timestamp int64
+		cells     []string
+	}
+
+	var rows []simulationRow
+	for _, decision := range simulation.Simulated {
+		scalingType := "dynamic"
+		if decision.ScalingType == models.ScalingTypeScheduled {
+			scalingType = "scheduled"
+		}
+		rows = append(rows, simulationRow{decision.Timestamp, []string{
+			apihelper.TimeFormatter.Format(decision.Timestamp), "simulated", scalingType,
+			fmt.Sprintf("%d->%d", decision.OldInstances, decision.NewInstances), decision.Reason,
+		}})
+	}
+	for _, history := range simulation.Actual {
+		// scaling type, status, instance changes, time, action and error
+		row := apihelper.HistoryRow(history)
+		changes := row[2]
+		if history.Status == models.ScalingStatusFailed {
+			changes = "failed"
+		}
+		rows = append(rows, simulationRow{history.Timestamp, []string{row[3], "actual", row[0], changes, row[4]}})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].timestamp < rows[j].timestamp })
+
+	table := ui.NewTable(writer, simulationHeaders)
+	for _, row := range rows {
+		table.Add(row.cells)
+	}
+	table.Print()
+}
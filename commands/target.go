@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+)
+
+// TargetOptions are global options to work with apps outside of the org and
+// space targeted with the CF CLI, they are accepted by every command.
+type TargetOptions struct {
+	Org     string `long:"org" description:"look up the app in this org instead of the targeted org, requires --space"`
+	Space   string `long:"space" description:"look up the app in this space instead of the targeted space, in the targeted org unless --org is given"`
+	AppGUID string `long:"app-guid" description:"use the app with this GUID instead of looking up APP_NAME, which is then only shown in messages"`
+}
+
+// newCFClient creates a CF client that resolves apps and spaces with the
+// global target options.
+func newCFClient(cliConnection api.Connection) (*api.CFClient, error) {
+	cfclient, err := api.NewCFClient(cliConnection)
+	if err != nil {
+		return nil, err
+	}
+	cfclient.Target = api.Target{
+		Org:     AutoScaler.Target.Org,
+		Space:   AutoScaler.Target.Space,
+		AppGUID: AutoScaler.Target.AppGUID,
+	}
+	return cfclient, nil
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+	ctime "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
+)
+
+// TimeOption is the value of --since, --start or --end. It accepts values
+// starting with a dash such as -30m, which go-flags would otherwise reject as
+// an option.
+type TimeOption string
+
+func (option TimeOption) IsValidValue(value string) error {
+	if strings.HasPrefix(value, "--") {
+		return fmt.Errorf("expected a time, but got option `%s'", value)
+	}
+	return nil
+}
+
+// parseTimeRange resolves the time options against the same current time, the
+// start time defaults to 0, i.e. the very beginning, and the end time to now.
+func parseTimeRange(since, start, end TimeOption) (int64, int64, error) {
+	var (
+		now       = time.Now()
+		st  int64 = 0
+		et  int64 = now.UnixNano()
+		err error
+	)
+	if since != "" && start != "" {
+		return 0, 0, fmt.Errorf(ui.SinceWithStartTime)
+	}
+	if since != "" {
+		d, err := ctime.ParseDuration(strings.TrimPrefix(string(since), "-"))
+		if err != nil {
+			return 0, 0, fmt.Errorf(ui.InvalidSinceDuration, since)
+		}
+		st = now.Add(-d).UnixNano()
+	}
+	if start != "" {
+		st, err = ctime.ParseTime(string(start), now)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if end != "" {
+		et, err = ctime.ParseTime(string(end), now)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if st > et {
+		if start == "" {
+			start = since
+		}
+		return 0, 0, fmt.Errorf(ui.InvalidTimeRange, start, end)
+	}
+	return st, et, nil
+}
+
+// TimeDisplayOptions are the --timezone and --time-format options of the
+// commands printing timestamps.
+type TimeDisplayOptions struct {
+	Timezone   string `long:"timezone" description:"time zone of the printed timestamps: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules, default to the local time zone"`
+	TimeFormat string `long:"time-format" description:"format of the printed timestamps: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like \"2006-01-02 15:04 MST\", default to rfc3339"`
+}
+
+// validate checks the options that can be resolved without the API
+func (options TimeDisplayOptions) validate() error {
+	if options.Timezone != ctime.TimezonePolicy {
+		if _, err := ctime.LoadLocation(options.Timezone); err != nil {
+			return err
+		}
+	}
+	_, err := ctime.ParseLayout(options.TimeFormat)
+	return err
+}
+
+// exportTime tells whether exports get a formatted time next to the raw
+// timestamp, which is only the case if one of the options is given so that
+// the exported records stay stable for scripts.
+func (options TimeDisplayOptions) exportTime() bool {
+	return options.Timezone != "" || options.TimeFormat != ""
+}
+
+// formatter resolves the options, the time zone of the schedules is looked up
+// with the API helper.
+func (options TimeDisplayOptions) formatter(apihelper *api.APIHelper, appName string) (ctime.Formatter, error) {
+	if options.Timezone != ctime.TimezonePolicy {
+		return ctime.NewFormatter(options.Timezone, options.TimeFormat)
+	}
+
+	policy, err := apihelper.GetScalingPolicy()
+	if err != nil {
+		return ctime.Formatter{}, err
+	}
+	timezone := policyTimezone(policy)
+	if timezone == "" {
+		return ctime.Formatter{}, fmt.Errorf(ui.NoPolicyTimezone, appName)
+	}
+	return ctime.NewFormatter(timezone, options.TimeFormat)
+}
+
+func policyTimezone(policy *models.ScalingPolicy) string {
+	if policy == nil || policy.Schedules == nil {
+		return ""
+	}
+	return policy.Schedules.Timezone
+}
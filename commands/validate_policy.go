@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+type ValidatePolicyCommand struct {
+	RequiredlArgs ValidatePolicyPositionalArgs `positional-args:"yes"`
+}
+
+type ValidatePolicyPositionalArgs struct {
+	PolicyFile string `positional-arg-name:"PATH_TO_POLICY_FILE" required:"true"`
+}
+
+func (command ValidatePolicyCommand) Execute([]string) error {
+	return ValidatePolicy(command.RequiredlArgs.PolicyFile)
+}
+
+func ValidatePolicy(policyFile string) error {
+
+	ui.SayMessage(ui.ValidatePolicyHint, policyFile)
+	_, _, err := loadPolicyFile(policyFile)
+	if err != nil {
+		return err
+	}
+
+	ui.SayOK()
+	return nil
+}
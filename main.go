@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/cli/v8/plugin"
 	flags "github.com/jessevdk/go-flags"
@@ -32,10 +33,18 @@ var BuildVcsId string
 
 var BuildVcsIdDate string
 
+// targetOptionsUsage is appended to the usage of every command taking APP_NAME
+const targetOptionsUsage = `
+
+TARGET OPTIONS:
+	--org		Look up the app in this org instead of the targeted org, requires --space.
+	--space		Look up the app in this space instead of the targeted space, in the targeted org unless --org is given.
+	--app-guid	Use the app with this GUID without looking it up, APP_NAME is then only shown in messages.`
+
 func (as *AutoScaler) GetMetadata() plugin.PluginMetadata {
 	version := getVersion()
 
-	return plugin.PluginMetadata{
+	metadata := plugin.PluginMetadata{
 		Name:    "AutoScaler",
 		Version: version,
 		Commands: []plugin.Command{
@@ -44,11 +53,18 @@ func (as *AutoScaler) GetMetadata() plugin.PluginMetadata {
 				Alias:    "asa",
 				HelpText: "Set or view AutoScaler service API endpoint",
 				UsageDetails: plugin.Usage{
-					Usage: `cf autoscaling-api [URL] [--unset] [--skip-ssl-validation]
+					Usage: `cf autoscaling-api [URL] [--name PROFILE] [--unset] [--skip-ssl-validation | --ca-cert PATH]
+   cf autoscaling-api --list
+   cf autoscaling-api --remove PROFILE
 
+An api endpoint is stored per CF api, the one of the current CF api is used by all commands.
 OPTIONS:
-	--unset                 Unset the api endpoint,
-	--skip-ssl-validation   Skip verification of the api endpoint. Not recommended! Inherit "cf" --skip-ssl-validation setting by default`,
+	--unset                 Unset the api endpoint of the current CF api,
+	--skip-ssl-validation   Skip verification of the api endpoint. Not recommended! Inherit "cf" --skip-ssl-validation setting by default
+	--ca-cert               Trust the CA certificate in a PEM file to verify the api endpoint, in addition to the system CAs and the CAs of SSL_CERT_FILE and SSL_CERT_DIR.
+	--name                  Name the api endpoint of the current CF api as a profile.
+	--list                  List the api endpoints of all CF apis.
+	--remove                Remove the api endpoint of a profile or a CF api URL.`,
 				},
 			},
 			{
@@ -56,10 +72,11 @@ OPTIONS:
 				Alias:    "asp",
 				HelpText: "Retrieve the scaling policy of an application",
 				UsageDetails: plugin.Usage{
-					Usage: `cf autoscaling-policy APP_NAME [--output PATH_TO_FILE]
+					Usage: `cf autoscaling-policy APP_NAME [--format FORMAT] [--output PATH_TO_FILE]
 
 OPTIONS:
-	--output	Dump the policy to a file in JSON format`,
+	--format	Output format: json or yaml, default to json.
+	--output	Dump the policy to a file in the chosen format.`,
 				},
 			},
 			{
@@ -67,7 +84,35 @@ OPTIONS:
 				Alias:    "aasp",
 				HelpText: "Attach a scaling policy to an application",
 				UsageDetails: plugin.Usage{
-					Usage: `cf attach-autoscaling-policy APP_NAME PATH_TO_FILE`,
+					Usage: `cf attach-autoscaling-policy APP_NAME PATH_TO_FILE [--dry-run]
+   cf attach-autoscaling-policy (--apps PATTERN[,PATTERN...] | --label-selector SELECTOR) --policy PATH_TO_FILE [--dry-run]
+
+PATH_TO_FILE:
+	A policy file in JSON format, or in YAML format with a .yml or .yaml extension.
+	Use - to read the policy from stdin in either format.
+OPTIONS:
+	--dry-run		Show the changes to the attached policy without attaching the new one.
+	--apps			Attach the policy to the apps of the targeted space matching the comma-separated glob patterns, e.g. 'orders-*,payments'.
+	--label-selector	Attach the policy to the apps of the targeted space matching the label selector, e.g. 'team=checkout,env in (prod,staging)'.
+	--policy		The policy file to attach with --apps or --label-selector.`,
+				},
+			},
+			{
+				Name:     "validate-autoscaling-policy",
+				Alias:    "vasp",
+				HelpText: "Validate a scaling policy file without attaching it",
+				UsageDetails: plugin.Usage{
+					Usage: `cf validate-autoscaling-policy PATH_TO_POLICY_FILE`,
+				},
+			},
+			{
+				Name:     "autoscaling-policy-diff",
+				Alias:    "aspd",
+				HelpText: "Compare the scaling policy of an application with a policy file",
+				UsageDetails: plugin.Usage{
+					Usage: `cf autoscaling-policy-diff APP_NAME PATH_TO_FILE
+
+Exits with status 1 when the policies differ.`,
 				},
 			},
 			{
@@ -75,7 +120,40 @@ OPTIONS:
 				Alias:    "dasp",
 				HelpText: "Detach the scaling policy from an application",
 				UsageDetails: plugin.Usage{
-					Usage: `cf detach-as-policy APP_NAME`,
+					Usage: `cf detach-autoscaling-policy APP_NAME [--dry-run]
+
+OPTIONS:
+	--dry-run	Show the policy that would be detached without detaching it.`,
+				},
+			},
+			{
+				Name:     "edit-autoscaling-policy",
+				Alias:    "easp",
+				HelpText: "Edit the scaling policy of an application with $EDITOR",
+				UsageDetails: plugin.Usage{
+					Usage: `cf edit-autoscaling-policy APP_NAME [--format FORMAT]
+
+OPTIONS:
+	--format	Format of the policy in the editor: json or yaml, default to yaml.`,
+				},
+			},
+			{
+				Name:     "export-autoscaling-policies",
+				Alias:    "exasp",
+				HelpText: "Export the scaling policies of all applications in the targeted space",
+				UsageDetails: plugin.Usage{
+					Usage: `cf export-autoscaling-policies [--dir DIR]
+
+OPTIONS:
+	--dir		Directory to write one policy file per app and an index.json to, default to autoscaling-policies.`,
+				},
+			},
+			{
+				Name:     "import-autoscaling-policies",
+				Alias:    "imasp",
+				HelpText: "Attach exported scaling policies to the applications of the targeted space",
+				UsageDetails: plugin.Usage{
+					Usage: `cf import-autoscaling-policies DIR`,
 				},
 			},
 			{
@@ -83,15 +161,28 @@ OPTIONS:
 				Alias:    "asm",
 				HelpText: "Retrieve the metrics of an application",
 				UsageDetails: plugin.Usage{
-					Usage: `cf autoscaling-metrics APP_NAME METRIC_NAME [--start START_TIME] [--end END_TIME] [--asc] [--output PATH_TO_FILE]
+					Usage: `cf autoscaling-metrics APP_NAME METRIC_NAME [--since DURATION | --start START_TIME] [--end END_TIME] [--asc] [--format FORMAT] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+   cf autoscaling-metrics APP_NAME METRIC_NAME,METRIC_NAME... | --all-policy-metrics [--since DURATION | --start START_TIME] [--end END_TIME] [--asc] [--format FORMAT] [--summary] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+   cf autoscaling-metrics APP_NAME METRIC_NAME --chart[=STYLE] [--since DURATION | --start START_TIME] [--end END_TIME] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+   cf autoscaling-metrics APP_NAME METRIC_NAME --summary [--since DURATION | --start START_TIME] [--end END_TIME] [--format FORMAT] [--output PATH_TO_FILE]
+   cf autoscaling-metrics APP_NAME METRIC_NAME --watch [--since DURATION | --start START_TIME] [--interval INTERVAL] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
 
 METRIC_NAME:
-	memoryused, memoryutil, responsetime, throughput, cpu or custom metric names.
+	memoryused, memoryutil, responsetime, throughput, cpu or custom metric names, or a comma-separated list of them.
 OPTIONS:
-	--start		Start time of metrics collected with format "yyyy-MM-ddTHH:mm:ss+/-HH:mm" or "yyyy-MM-ddTHH:mm:ssZ", default to very beginning if not specified.
-	--end		End time of the metrics collected with format "yyyy-MM-ddTHH:mm:ss+/-HH:mm" or "yyyy-MM-ddTHH:mm:ssZ", default to current time if not speficied.
+	--all-policy-metrics	Retrieve the metric types of all scaling rules of the app's policy instead of METRIC_NAME.
+	--since		Show the metrics of the given duration before now, e.g. 30m, 2h or 7d. Cannot be used with --start.
+	--start		Start time of the metrics, default to very beginning if not specified. Supported formats are "yyyy-MM-ddTHH:mm:ss+/-HH:mm", "yyyy-MM-ddTHH:mm:ssZ", "yyyy-MM-ddTHH:mm:ss" and "yyyy-MM-dd" in the local time zone, "now", a duration before now like "-30m" and Unix epoch seconds or milliseconds.
+	--end		End time of the metrics in the same formats as --start, default to current time if not specified.
 	--asc		Display in ascending order, default to descending order if not specified.
-	--output	Dump the metrics to a file in table format.
+	--format	Output format: table, json, jsonl or csv, default to table. All pages are retrieved for json, jsonl and csv.
+	--output	Dump the metrics to a file in the chosen format.
+	--chart		Draw the metrics in the time range as a line chart, or a sparkline with --chart=sparkline, sized to the terminal width with the thresholds of the policy as horizontal lines. --output writes the plain table.
+	--summary	Print count, min, max, mean, median, p90, p95, p99 and standard deviation of all metrics in the time range.
+	--watch, -w	Keep polling and print new metrics of a single metric name annotated with the thresholds they cross until interrupted, only the table format is supported.
+	--interval	Poll interval of --watch, e.g. 30s or 1m, default to 10s.
+	--timezone	Time zone of the timestamps: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules, default to the local time zone.
+	--time-format	Format of the timestamps: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like "2006-01-02 15:04 MST", default to rfc3339. Exports get an additional time column if --timezone or --time-format is given.
 					`,
 				},
 			},
@@ -100,18 +191,120 @@ OPTIONS:
 				Alias:    "ash",
 				HelpText: "Retrieve the scaling history of an application",
 				UsageDetails: plugin.Usage{
-					Usage: `cf autoscaling-history APP_NAME [--start START_TIME] [--end END_TIME] [--asc] [--output PATH_TO_FILE]
+					Usage: `cf autoscaling-history APP_NAME [--since DURATION | --start START_TIME] [--end END_TIME] [--asc] [--status STATUS] [--type TYPE] [--direction DIRECTION] [--reason-contains TEXT] [--format FORMAT] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+   cf autoscaling-history APP_NAME --follow [--since DURATION | --start START_TIME] [--interval INTERVAL] [--status STATUS] [--type TYPE] [--direction DIRECTION] [--reason-contains TEXT] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
 
 OPTIONS:
-	--start		Start time of the scaling history with format "yyyy-MM-ddTHH:mm:ss+/-HH:mm" or "yyyy-MM-ddTHH:mm:ssZ", default to very beginning if not specified.
-	--end		End time of the scaling history with format "yyyy-MM-ddTHH:mm:ss+/-HH:mm" or "yyyy-MM-ddTHH:mm:ssZ", default to current time if not speficied.
+	--since		Show the scaling history of the given duration before now, e.g. 30m, 2h or 7d. Cannot be used with --start.
+	--start		Start time of the scaling history, default to very beginning if not specified. Supported formats are "yyyy-MM-ddTHH:mm:ss+/-HH:mm", "yyyy-MM-ddTHH:mm:ssZ", "yyyy-MM-ddTHH:mm:ss" and "yyyy-MM-dd" in the local time zone, "now", a duration before now like "-30m" and Unix epoch seconds or milliseconds.
+	--end		End time of the scaling history in the same formats as --start, default to current time if not specified.
 	--asc		Display in ascending order, default to descending order if not specified.
-	--output	Dump the scaling history to a file in table format.
+	--format	Output format: table, json, jsonl or csv, default to table. All pages are retrieved for json, jsonl and csv.
+	--output	Dump the scaling history to a file in the chosen format.
+	--status	Only show scaling events with the status succeeded or failed.
+	--type		Only show scaling events of the scaling type dynamic or scheduled.
+	--direction	Only show scaling events adding (out) or removing (in) instances.
+	--reason-contains	Only show scaling events whose reason or message contains the given text, case-insensitive.
+	--follow, -f	Keep polling and print new scaling events until interrupted, only the table format is supported.
+	--interval	Poll interval of --follow, e.g. 30s or 1m, default to 10s.
+	--timezone	Time zone of the timestamps: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules, default to the local time zone.
+	--time-format	Format of the timestamps: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like "2006-01-02 15:04 MST", default to rfc3339. Exports get an additional time column if --timezone or --time-format is given.
+					`,
+				},
+			},
+			{
+				Name:     "create-autoscaling-credential",
+				Alias:    "casc",
+				HelpText: "Create custom metric credential for an application",
+				UsageDetails: plugin.Usage{
+					Usage: `cf create-autoscaling-credential APP_NAME [--credential-file PATH_TO_FILE] [--output PATH_TO_FILE]
+
+OPTIONS:
+	--credential-file	Use the username and password defined in a JSON file, a random credential is generated if not specified.
+	--output		Dump the credential to a file in JSON format.
+					`,
+				},
+			},
+			{
+				Name:     "delete-autoscaling-credential",
+				Alias:    "dasc",
+				HelpText: "Delete the custom metric credential of an application",
+				UsageDetails: plugin.Usage{
+					Usage: `cf delete-autoscaling-credential APP_NAME`,
+				},
+			},
+			{
+				Name:     "autoscaling-apps",
+				Alias:    "asapps",
+				HelpText: "List the apps of the targeted space with their autoscaling settings",
+				UsageDetails: plugin.Usage{
+					Usage: `cf autoscaling-apps [--timezone TIMEZONE] [--time-format TIME_FORMAT]
+
+OPTIONS:
+	--timezone	Time zone of the last scaling event: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of each app's schedules, default to the local time zone.
+	--time-format	Format of the last scaling event: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like "2006-01-02 15:04 MST", default to rfc3339.
+					`,
+				},
+			},
+			{
+				Name:     "autoscaling-report",
+				Alias:    "asr",
+				HelpText: "Report the scaling behavior of an application",
+				UsageDetails: plugin.Usage{
+					Usage: `cf autoscaling-report APP_NAME [--since DURATION | --start START_TIME] [--end END_TIME] [--format FORMAT] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+
+OPTIONS:
+	--since		Report on the given duration before now, e.g. 30m, 2h or 7d. Cannot be used with --start, default to 7d if neither is specified.
+	--start		Start time of the report in the same formats as autoscaling-history.
+	--end		End time of the report in the same formats as --start, default to current time if not specified.
+	--format	Output format: text or json, default to text.
+	--output	Dump the report to a file in the chosen format.
+	--timezone	Time zone of the timestamps: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules, default to the local time zone.
+	--time-format	Format of the timestamps: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like "2006-01-02 15:04 MST", default to rfc3339. The json format keeps the raw timestamps.
+					`,
+				},
+			},
+			{
+				Name:     "autoscaling-simulate",
+				Alias:    "assim",
+				HelpText: "Simulate a scaling policy against the metrics of an application",
+				UsageDetails: plugin.Usage{
+					Usage: `cf autoscaling-simulate APP_NAME PATH_TO_POLICY_FILE [--since DURATION | --start START_TIME] [--end END_TIME] [--format FORMAT] [--timezone TIMEZONE] [--time-format TIME_FORMAT] [--output PATH_TO_FILE]
+
+OPTIONS:
+	--since		Simulate the given duration before now, e.g. 30m, 2h or 7d. Cannot be used with --start, default to 7d if neither is specified.
+	--start		Start time of the simulation in the same formats as autoscaling-history.
+	--end		End time of the simulation in the same formats as --start, default to current time if not specified.
+	--format	Output format: table or json, default to table.
+	--output	Dump the simulation to a file in the chosen format.
+	--timezone	Time zone of the timestamps: an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules, default to the local time zone.
+	--time-format	Format of the timestamps: rfc3339, rfc3339nano, datetime, time, unix or a Go layout like "2006-01-02 15:04 MST", default to rfc3339. The json format keeps the raw timestamps.
+					`,
+				},
+			},
+			{
+				Name:     "autoscaling-schedule",
+				Alias:    "assch",
+				HelpText: "Preview the upcoming schedule windows of a scaling policy",
+				UsageDetails: plugin.Usage{
+					Usage: `cf autoscaling-schedule APP_NAME [--next N] [--from PATH_TO_POLICY_FILE]
+   cf autoscaling-schedule --from PATH_TO_POLICY_FILE [--next N]
+
+OPTIONS:
+	--next		Number of upcoming schedule windows to show, default to 10.
+	--from		Read the schedules from a policy file instead of the policy attached to the app, APP_NAME is optional then.
 					`,
 				},
 			},
 		},
 	}
+
+	for i, command := range metadata.Commands {
+		if strings.Contains(command.UsageDetails.Usage, " APP_NAME") {
+			metadata.Commands[i].UsageDetails.Usage = strings.TrimRight(command.UsageDetails.Usage, " \t\n") + targetOptionsUsage
+		}
+	}
+	return metadata
 }
 
 func getVersion() plugin.VersionType {
@@ -148,6 +341,9 @@ func (as *AutoScaler) Run(cliConnection plugin.CliConnection, args []string) {
 	parser.NamespaceDelimiter = "-"
 
 	_, err := parser.ParseArgs(args)
+	if exitErr, ok := err.(*commands.ExitCodeError); ok {
+		os.Exit(exitErr.Code)
+	}
 	if err != nil {
 		ui.SayFailed()
 		ui.SayMessage("Error: %s", err.Error())
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"code.cloudfoundry.org/cli/v8/cf/util/testhelpers/rpcserver"
@@ -22,6 +26,7 @@ import (
 	"github.com/onsi/gomega/gexec"
 	"github.com/onsi/gomega/ghttp"
 	. "github.com/onsi/gomega/gstruct"
+	"go.yaml.in/yaml/v3"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/api"
 	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
@@ -86,7 +91,7 @@ var _ = Describe("App-AutoScaler Commands", func() {
 				SpecificDateSchedules: []*SpecificDateSchedule{
 					{
 						StartDateTime:         "2006-01-02T15:04",
-						EndDateTime:           "2006-01-02T15:04",
+						EndDateTime:           "2006-01-03T15:04",
 						ScheduledInstanceMin:  10,
 						ScheduledInstanceMax:  50,
 						ScheduledInstanceInit: 30,
@@ -272,6 +277,20 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					Expect(session.ExitCode()).To(Equal(0))
 				})
 
+				It("fails with a missing --ca-cert file", func() {
+					args = []string{"autoscaling-api", apiTLSEndpoint.String(), "--ca-cert", "missing.pem"}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say("Failed to load CA certificate .*missing.pem"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("fails with both --ca-cert and --skip-ssl-validation", func() {
+					args = []string{"autoscaling-api", apiTLSEndpoint.String(), "--ca-cert", "ca.pem", "--skip-ssl-validation"}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say(ui.CACertWithSkipSSL))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
 				It("attach 'https' as the default protocol when prefix is missing ", func() {
 					args = []string{"autoscaling-api", strings.TrimPrefix(apiTLSEndpoint.String(), "https://"), "--skip-ssl-validation"}
 					session := runPluginCommand(ts, args...)
@@ -402,6 +421,67 @@ var _ = Describe("App-AutoScaler Commands", func() {
 
 		})
 
+		Context("Endpoints of several CF apis", func() {
+
+			BeforeEach(func() {
+				urlConfig := []byte(`{"Endpoints":{"https://api.bosh-lite.com":{"URL":"https://autoscaler.bosh-lite.com","Profile":"lite"}}}`)
+				err = ioutil.WriteFile(api.ConfigFile(), urlConfig, 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("keeps the endpoint of each CF api and picks the one of the current CF api", func() {
+				session := runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String(), "--name", "dev")
+				Expect(session.ExitCode()).To(Equal(0))
+
+				session = runPluginCommand(ts, "autoscaling-api", "--list")
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(ui.ListAPIEndpoints)))
+				Expect(session.Out).To(gbytes.Say(`Profile\s+CF API\s+AutoScaler API\s+Skip SSL Validation`))
+				Expect(session.Out).To(gbytes.Say(`\*\s+dev\s+` + regexp.QuoteMeta(cloudControllerEndpoint.String()) + `\s+` + regexp.QuoteMeta(autoscalerEndpoint.String()) + `\s+false`))
+				Expect(session.Out).To(gbytes.Say(`lite\s+https://api.bosh-lite.com\s+https://autoscaler.bosh-lite.com\s+false`))
+				Expect(session.ExitCode()).To(Equal(0))
+
+				rpcHandlers.ApiEndpointStub = func(_ string, retVal *string) error {
+					*retVal = "https://api.bosh-lite.com"
+					return nil
+				}
+				session = runPluginCommand(ts, "autoscaling-api")
+				Expect(session).To(gbytes.Say(ui.APIEndpoint, "https://autoscaler.bosh-lite.com"))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("names the endpoint of the current CF api", func() {
+				session := runPluginCommand(ts, "autoscaling-api", "--name", "dev")
+				Expect(session).To(gbytes.Say(ui.NameAPIEndpoint, cloudControllerEndpoint, "dev"))
+				Expect(session.ExitCode()).To(Equal(0))
+
+				session = runPluginCommand(ts, "autoscaling-api", "--list")
+				Expect(session.Out).To(gbytes.Say(`\*\s+dev\s+` + regexp.QuoteMeta(cloudControllerEndpoint.String())))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("fails to name two endpoints alike", func() {
+				session := runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String(), "--name", "lite")
+				Expect(session).To(gbytes.Say(ui.ProfileInUse, "lite", "https://api.bosh-lite.com"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("removes the endpoint of a profile", func() {
+				session := runPluginCommand(ts, "autoscaling-api", "--remove", "lite")
+				Expect(session).To(gbytes.Say(ui.RemoveAPIEndpoint, "https://api.bosh-lite.com"))
+				Expect(session.ExitCode()).To(Equal(0))
+
+				session = runPluginCommand(ts, "autoscaling-api", "--list")
+				Expect(session.Out).NotTo(gbytes.Say("bosh-lite"))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("fails to remove an unknown profile", func() {
+				session := runPluginCommand(ts, "autoscaling-api", "--remove", "prod")
+				Expect(session).To(gbytes.Say(ui.NoAPIEndpointFor, "prod"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+		})
+
 	})
 
 	Describe("Commands autoscaling-policy, asp", func() {
@@ -715,6 +795,23 @@ var _ = Describe("App-AutoScaler Commands", func() {
 									})
 
 								})
+
+								It("Succeed to print the policy in YAML format", func() {
+									args = []string{"autoscaling-policy", fakeAppName, "--format", "yaml", "--output", outputFile}
+									session := runPluginCommand(ts, args...)
+
+									Expect(session.Out).To(gbytes.Say(ui.SavePolicyHint, fakeAppName, outputFile))
+									Expect(session.ExitCode()).To(Equal(0))
+
+									contents, err := os.ReadFile(outputFile)
+									Expect(err).NotTo(HaveOccurred())
+									Expect(string(contents)).To(HavePrefix("instance_min_count: 1\ninstance_max_count: 2\nscaling_rules:\n  - metric_type: memoryused\n"))
+
+									var actualPolicy ScalingPolicy
+									err = yaml.Unmarshal(contents, &actualPolicy)
+									Expect(err).NotTo(HaveOccurred())
+									Expect(actualPolicy).To(Equal(fakePolicy))
+								})
 							})
 
 						})
@@ -945,14 +1042,6 @@ var _ = Describe("App-AutoScaler Commands", func() {
 												ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
 											),
 										)
-
-										fakePolicy.InstanceMin = 10
-										fakePolicy.InstanceMax = 2
-										policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
-										Expect(err).NotTo(HaveOccurred())
-										err = os.WriteFile(outputFile, policyBytes, 0666)
-										Expect(err).NotTo(HaveOccurred())
-
 									})
 
 									It("Failed with 400", func() {
@@ -976,14 +1065,6 @@ var _ = Describe("App-AutoScaler Commands", func() {
 												ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
 											),
 										)
-
-										fakePolicy.InstanceMin = 10
-										fakePolicy.InstanceMax = 2
-										policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
-										Expect(err).NotTo(HaveOccurred())
-										err = os.WriteFile(outputFile, policyBytes, 0666)
-										Expect(err).NotTo(HaveOccurred())
-
 									})
 
 									It("Failed with 400", func() {
@@ -999,6 +1080,35 @@ var _ = Describe("App-AutoScaler Commands", func() {
 									})
 								})
 
+								When("attached policy definition fails the local validation", func() {
+									BeforeEach(func() {
+										apiServer.RouteToHandler("PUT", urlpath,
+											ghttp.RespondWith(http.StatusCreated, ""),
+										)
+
+										invalidPolicy := []byte(`{"instance_min_count":10,"instance_max_count":2,"scaling_rules":[{"metric_type":"memoryused","threshold":30,"operator":"=>","adjustment":"1"}]}`)
+										err = os.WriteFile(outputFile, invalidPolicy, 0666)
+										Expect(err).NotTo(HaveOccurred())
+									})
+
+									It("Failed without sending the policy", func() {
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, outputFile}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session).To(gbytes.Say("FAILED"))
+										Expect(session).To(gbytes.Say("instance_min_count: instance_min_count 10 is higher than instance_max_count 2"))
+										Expect(session).To(gbytes.Say(`scaling_rules\[0\].operator: "=>" must be one of <, >, <=, >=`))
+										Expect(session).To(gbytes.Say(`scaling_rules\[0\].adjustment: "1" must be a signed number`))
+										Expect(session.ExitCode()).To(Equal(1))
+
+										for _, req := range apiServer.ReceivedRequests() {
+											Expect(req.Method).NotTo(Equal("PUT"))
+										}
+									})
+								})
+
 								When("No policy defined previously", func() {
 									BeforeEach(func() {
 										apiServer.RouteToHandler("PUT", urlpath,
@@ -1020,6 +1130,206 @@ var _ = Describe("App-AutoScaler Commands", func() {
 									})
 								})
 
+								When("policy file is written in yaml format", func() {
+									var yamlPolicyFile = "policy.yml"
+
+									BeforeEach(func() {
+										policyBytes, err := yaml.Marshal(fakePolicy)
+										Expect(err).NotTo(HaveOccurred())
+										err = os.WriteFile(yamlPolicyFile, policyBytes, 0666)
+										Expect(err).NotTo(HaveOccurred())
+
+										apiServer.RouteToHandler("PUT", urlpath,
+											ghttp.CombineHandlers(
+												ghttp.VerifyJSONRepresenting(fakePolicy),
+												ghttp.RespondWith(http.StatusCreated, ""),
+												ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+											),
+										)
+									})
+
+									AfterEach(func() {
+										os.Remove(yamlPolicyFile)
+									})
+
+									It("Succeed with the policy converted to JSON", func() {
+										args = []string{"attach-autoscaling-policy", fakeAppName, yamlPolicyFile}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed with unquoted dates and adjustments written by hand", func() {
+										Expect(os.WriteFile(yamlPolicyFile, []byte(`instance_min_count: 1
+instance_max_count: 4
+owner: checkout
+scaling_rules:
+  - metric_type: memoryused
+    threshold: 80
+    operator: ">="
+    adjustment: +1
+schedules:
+  timezone: UTC
+  recurring_schedule:
+    - start_date: 2099-01-01
+      end_date: 2099-12-31
+      start_time: "08:00"
+      end_time: "18:00"
+      days_of_week: [1, 2, 3, 4, 5]
+      instance_min_count: 2
+      instance_max_count: 4
+`), 0666)).To(Succeed())
+										apiServer.RouteToHandler("PUT", urlpath,
+											ghttp.CombineHandlers(
+												ghttp.VerifyJSON(`{
+													"instance_min_count": 1,
+													"instance_max_count": 4,
+													"owner": "checkout",
+													"scaling_rules": [{"metric_type": "memoryused", "threshold": 80, "operator": ">=", "adjustment": "+1"}],
+													"schedules": {
+														"timezone": "UTC",
+														"recurring_schedule": [{"start_date": "2099-01-01", "end_date": "2099-12-31", "start_time": "08:00", "end_time": "18:00",
+															"days_of_week": [1, 2, 3, 4, 5], "instance_min_count": 2, "instance_max_count": 4}]
+													}}`),
+												ghttp.RespondWith(http.StatusCreated, ""),
+											),
+										)
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, yamlPolicyFile}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+								})
+
+								When("running with --dry-run", func() {
+									BeforeEach(func() {
+										apiServer.RouteToHandler("PUT", urlpath,
+											ghttp.RespondWith(http.StatusCreated, ""),
+										)
+									})
+
+									AfterEach(func() {
+										for _, req := range apiServer.ReceivedRequests() {
+											Expect(req.Method).NotTo(Equal("PUT"))
+										}
+									})
+
+									It("shows the changes to the attached policy", func() {
+										attachedPolicy := fakePolicy
+										attachedPolicy.InstanceMax = 4
+										apiServer.RouteToHandler("GET", urlpath,
+											ghttp.CombineHandlers(
+												ghttp.RespondWithJSONEncoded(http.StatusOK, &attachedPolicy),
+												ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+											),
+										)
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, outputFile, "--dry-run"}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(`Attaching policy for app fakeAppName \(dry run\)...`))
+										Expect(session.Out).To(gbytes.Say(`~ instance_max_count: 4 -> 2`))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.Out).To(gbytes.Say("TIP: This was a dry run"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("shows the whole policy as added when no policy is attached", func() {
+										apiServer.RouteToHandler("GET", urlpath,
+											ghttp.RespondWith(http.StatusNotFound, ""),
+										)
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, outputFile, "--dry-run"}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.NoPolicyAttached, fakeAppName))
+										Expect(session.Out).To(gbytes.Say(`\+ scaling_rules\[0\]: metric_type memoryused`))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("fails the local validation", func() {
+										invalidPolicy := []byte(`{"instance_min_count":10,"instance_max_count":2,"scaling_rules":[{"metric_type":"memoryused","threshold":30,"operator":">","adjustment":"+1"}]}`)
+										err = os.WriteFile(outputFile, invalidPolicy, 0666)
+										Expect(err).NotTo(HaveOccurred())
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, outputFile, "--dry-run"}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session).To(gbytes.Say("instance_min_count: instance_min_count 10 is higher than instance_max_count 2"))
+										Expect(session.ExitCode()).To(Equal(1))
+									})
+								})
+
+								When("policy is read from stdin", func() {
+									BeforeEach(func() {
+										apiServer.RouteToHandler("PUT", urlpath,
+											ghttp.CombineHandlers(
+												ghttp.VerifyJSONRepresenting(fakePolicy),
+												ghttp.RespondWith(http.StatusCreated, ""),
+												ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+											),
+										)
+									})
+
+									It("Succeed with a JSON policy", func() {
+										policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
+										Expect(err).NotTo(HaveOccurred())
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, "-"}
+										session := runPluginCommandWithStdin(ts, bytes.NewReader(policyBytes), args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed with a YAML policy", func() {
+										policyBytes, err := yaml.Marshal(fakePolicy)
+										Expect(err).NotTo(HaveOccurred())
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, "-"}
+										session := runPluginCommandWithStdin(ts, bytes.NewReader(policyBytes), args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed with a flow style YAML policy", func() {
+										var node yaml.Node
+										Expect(node.Encode(fakePolicy)).To(Succeed())
+										node.Style = yaml.FlowStyle
+										policyBytes, err := yaml.Marshal(&node)
+										Expect(err).NotTo(HaveOccurred())
+										Expect(bytes.HasPrefix(policyBytes, []byte("{"))).To(BeTrue())
+										Expect(json.Valid(policyBytes)).To(BeFalse())
+
+										args = []string{"attach-autoscaling-policy", fakeAppName, "-"}
+										session := runPluginCommandWithStdin(ts, bytes.NewReader(policyBytes), args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.AttachPolicyHint, fakeAppName))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Failed with an empty stdin", func() {
+										args = []string{"attach-autoscaling-policy", fakeAppName, "-"}
+										session := runPluginCommandWithStdin(ts, strings.NewReader(""), args...)
+
+										Expect(session).To(gbytes.Say("FAILED"))
+										Expect(session.ExitCode()).To(Equal(1))
+										for _, req := range apiServer.ReceivedRequests() {
+											Expect(req.Method).NotTo(Equal("PUT"))
+										}
+									})
+								})
+
 								When("policy exist previously ", func() {
 									BeforeEach(func() {
 										apiServer.RouteToHandler("PUT", urlpath,
@@ -1051,86 +1361,393 @@ var _ = Describe("App-AutoScaler Commands", func() {
 
 			})
 		})
-	})
 
-	Describe("Commands detach-autoscaling-policy, dasp", func() {
+		Context("attach-autoscaling-policy with --apps or --label-selector", func() {
 
-		var urlpath = "/v1/apps/" + fakeAppID + "/policy"
-		Context("detach-autoscaling-policy", func() {
+			const otherAppID, otherAppName, thirdAppID, thirdAppName = "otherAppId", "orders-api", "thirdAppId", "orders-worker"
+			var (
+				attached     []string
+				healthChecks int32
+			)
 
-			When("the args are not properly provided", func() {
-				It("Require APP_NAME as argument", func() {
-					args = []string{"detach-autoscaling-policy"}
-					session := runPluginCommand(ts, args...)
+			It("Require the --policy option", func() {
+				session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "orders-*")
 
-					Expect(session).To(gbytes.Say("required argument `APP_NAME` was not provided"))
-					Expect(session.ExitCode()).To(Equal(1))
-				})
+				Expect(session).To(gbytes.Say("the required option `--policy` was not provided"))
+				Expect(session.ExitCode()).To(Equal(1))
 			})
 
-			When("cf api is not set ", func() {
-				BeforeEach(func() {
+			It("Failed with APP_NAME", func() {
+				session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "orders-*", fakeAppName, "--policy", outputFile)
 
-					rpcHandlers.ApiEndpointStub = func(_ string, retVal *string) error {
-						*retVal = ""
-						return nil
-					}
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(ui.AppNameWithBulkAttach)))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
 
-					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
-					runPluginCommand(ts, args...)
+			It("Failed with the policy file as argument", func() {
+				session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "orders-*", outputFile)
 
-					rpcHandlers.ApiEndpointStub = func(_ string, retVal *string) error {
-						*retVal = ""
-						return nil
-					}
-				})
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(ui.AppNameWithBulkAttach)))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
 
-				It("Failed with missing cf api setting", func() {
-					args = []string{"detach-autoscaling-policy", fakeAppName}
-					session := runPluginCommand(ts, args...)
-					Expect(session).To(gbytes.Say(ui.NOCFAPIEndpoint))
-					Expect(session.ExitCode()).To(Equal(1))
-				})
+			It("Failed with --policy and APP_NAME", func() {
+				session := runPluginCommand(ts, "attach-autoscaling-policy", fakeAppName, "--policy", outputFile)
+
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(ui.PolicyWithAppName)))
+				Expect(session.ExitCode()).To(Equal(1))
 			})
 
-			When("cf api is changed ", func() {
+			It("Failed with an invalid pattern", func() {
+				session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "orders-[", "--policy", outputFile)
+
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.InvalidAppPattern, "orders-["))))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			When("logged in and targeting a space", func() {
 				BeforeEach(func() {
-					urlConfig := []byte(fmt.Sprintf(`{"URL":"%s"}`, "autoscaler.bosh-lite.com"))
-					err = ioutil.WriteFile(api.ConfigFile(), urlConfig, 0600)
+					attached = nil
+					atomic.StoreInt32(&healthChecks, 0)
+					setLoggedIn(rpcHandlers)
+					setTargeted(rpcHandlers)
+					policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
 					Expect(err).NotTo(HaveOccurred())
+					Expect(os.WriteFile(outputFile, policyBytes, 0666)).To(Succeed())
 
+					apiServer.RouteToHandler("GET", "/v3/apps",
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[
+							{"guid": "%s", "name": "%s"},
+							{"guid": "%s", "name": "%s"},
+							{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName, otherAppID, otherAppName, thirdAppID, thirdAppName)),
+					)
+					apiServer.RouteToHandler("GET", "/v3/processes",
+						ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+					)
+					apiServer.RouteToHandler("GET", "/health",
+						func(w http.ResponseWriter, req *http.Request) {
+							atomic.AddInt32(&healthChecks, 1)
+							ghttp.RespondWith(http.StatusOK, "")(w, req)
+						},
+					)
+					apiServer.RouteToHandler("PUT", "/v1/apps/"+otherAppID+"/policy",
+						func(w http.ResponseWriter, req *http.Request) {
+							attached = append(attached, otherAppID)
+							ghttp.RespondWith(http.StatusOK, "")(w, req)
+						},
+					)
+					apiServer.RouteToHandler("PUT", "/v1/apps/"+thirdAppID+"/policy",
+						func(w http.ResponseWriter, req *http.Request) {
+							attached = append(attached, thirdAppID)
+							ghttp.RespondWith(http.StatusInternalServerError, `{"error": "internal error"}`)(w, req)
+						},
+					)
 				})
 
-				When("the default endpoint doesn't work", func() {
-
-					BeforeEach(func() {
-						apiServer.RouteToHandler("GET", "/health",
-							ghttp.RespondWith(http.StatusNotFound, ""),
-						)
-					})
-
-					It("Failed with no api endpoint setting", func() {
-						args = []string{"detach-autoscaling-policy", fakeAppName}
-						session := runPluginCommand(ts, args...)
-						Expect(session).To(gbytes.Say(ui.NoEndpoint))
-						Expect(session.ExitCode()).To(Equal(1))
-					})
+				JustBeforeEach(func() {
+					runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+					atomic.StoreInt32(&healthChecks, 0)
 				})
-			})
 
-			When("cf not login", func() {
-				It("exits with 'You must be logged in' error ", func() {
-					args = []string{"detach-autoscaling-policy", fakeAppName}
-					session := runPluginCommand(ts, args...)
-					Expect(session).To(gbytes.Say("You must be logged in"))
+				It("attaches the policy to the apps matching the patterns and reports each app", func() {
+					session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "orders-*", "--policy", outputFile)
+
+					Expect(session.Out).To(gbytes.Say(ui.BulkAttachHint, outputFile, 2, "fakeSpace"))
+					Expect(session.Out).To(gbytes.Say(`App Name\s+Status\s+Details`))
+					Expect(session.Out).To(gbytes.Say(otherAppName + `\s+attached`))
+					Expect(session.Out).To(gbytes.Say(thirdAppName + `\s+failed\s+internal error`))
+					Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.BulkAttachSummary, 1, 1))))
 					Expect(session.ExitCode()).To(Equal(1))
+					Expect(attached).To(ConsistOf(otherAppID, thirdAppID))
+					Expect(atomic.LoadInt32(&healthChecks)).To(Equal(int32(1)))
 				})
-			})
 
-			When("logged in to CF", func() {
-				BeforeEach(func() {
+				It("selects the apps by label", func() {
+					apiServer.RouteToHandler("GET", "/v3/apps",
+						ghttp.CombineHandlers(
+							ghttp.VerifyFormKV("label_selector", "team in (orders,payments)"),
+							ghttp.VerifyFormKV("space_guids", "fakeSpaceGuid"),
+							ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, otherAppID, otherAppName)),
+						),
+					)
+					session := runPluginCommand(ts, "attach-autoscaling-policy", "--label-selector", "team in (orders, payments)", "--policy", outputFile)
 
-					setLoggedIn(rpcHandlers)
+					Expect(session.Out).To(gbytes.Say(otherAppName + `\s+attached`))
+					Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.BulkAttachSummary, 1, 0))))
+					Expect(session.ExitCode()).To(Equal(0))
+					Expect(attached).To(Equal([]string{otherAppID}))
+				})
+
+				It("Failed with an invalid label selector", func() {
+					session := runPluginCommand(ts, "attach-autoscaling-policy", "--label-selector", "team=orders,", "--policy", outputFile)
+
+					Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.InvalidLabelSelector, "team=orders,", ""))))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when no app matches", func() {
+					session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", "billing-*", "--policy", outputFile)
+
+					Expect(session).To(gbytes.Say(ui.NoAppsMatched, "fakeSpace"))
+					Expect(session.ExitCode()).To(Equal(1))
+					Expect(attached).To(BeEmpty())
+				})
+
+				It("shows the changes per app with --dry-run", func() {
+					apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+						ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+					)
+					apiServer.RouteToHandler("GET", "/v1/apps/"+otherAppID+"/policy",
+						ghttp.RespondWith(http.StatusNotFound, ""),
+					)
+					session := runPluginCommand(ts, "attach-autoscaling-policy", "--apps", fakeAppName+","+otherAppName, "--policy", outputFile, "--dry-run")
+
+					Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.DryRunBulkAttachHint, outputFile, 2, "fakeSpace"))))
+					Expect(session.Out).To(gbytes.Say(fakeAppName + `\s+unchanged`))
+					Expect(session.Out).To(gbytes.Say(otherAppName + `\s+would change\s+` + regexp.QuoteMeta(fmt.Sprintf(ui.NoPolicyAttached, otherAppName))))
+					Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.DryRunBulkAttachSummary, 1, 1, 0))))
+					Expect(session.Out).To(gbytes.Say(ui.DryRunWarning))
+					Expect(session.ExitCode()).To(Equal(0))
+					Expect(attached).To(BeEmpty())
+				})
+			})
+		})
+	})
+
+	Describe("Commands validate-autoscaling-policy, vasp", func() {
+
+		Context("validate-autoscaling-policy", func() {
+
+			It("Require PATH_TO_POLICY_FILE as argument", func() {
+				args = []string{"validate-autoscaling-policy"}
+				session := runPluginCommand(ts, args...)
+
+				Expect(session).To(gbytes.Say("the required argument `PATH_TO_POLICY_FILE` was not provided"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("Failed when policy file not exist", func() {
+				args = []string{"validate-autoscaling-policy", outputFile}
+				session := runPluginCommand(ts, args...)
+
+				Expect(session).To(gbytes.Say(ui.FailToLoadPolicyFile, outputFile))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("Succeed with a valid policy without being logged in", func() {
+				policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
+				Expect(err).NotTo(HaveOccurred())
+				err = os.WriteFile(outputFile, policyBytes, 0666)
+				Expect(err).NotTo(HaveOccurred())
+
+				args = []string{"validate-autoscaling-policy", outputFile}
+				session := runPluginCommand(ts, args...)
+
+				Expect(session.Out).To(gbytes.Say(ui.ValidatePolicyHint, outputFile))
+				Expect(session.Out).To(gbytes.Say("OK"))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("Failed with the JSON path of every invalid field", func() {
+				invalidPolicy := []byte(`{
+					"instance_min_count": 1,
+					"instance_max_count": 5,
+					"scaling_rules": [{"metric_type": "memoryutil", "threshold": 150, "operator": ">", "adjustment": "+1"}],
+					"schedules": {
+						"timezone": "Mars/Olympus_Mons",
+						"recurring_schedule": [{"start_time": "25:00", "end_time": "18:00", "days_of_week": [1, 8], "instance_min_count": 1, "instance_max_count": 5}],
+						"specific_date": [
+							{"start_date_time": "2099-01-01T10:00", "end_date_time": "2099-01-02T10:00", "instance_min_count": 2, "instance_max_count": 5},
+							{"start_date_time": "2099-01-02T09:00", "end_date_time": "2099-01-03T10:00", "instance_min_count": 2, "instance_max_count": 5}
+						]
+					}
+				}`)
+				err = os.WriteFile(outputFile, invalidPolicy, 0666)
+				Expect(err).NotTo(HaveOccurred())
+
+				args = []string{"validate-autoscaling-policy", outputFile}
+				session := runPluginCommand(ts, args...)
+
+				Expect(session).To(gbytes.Say("FAILED"))
+				Expect(session).To(gbytes.Say(`scaling_rules\[0\].threshold: must be between 1 and 100 for metric type memoryutil`))
+				Expect(session).To(gbytes.Say(`schedules.timezone: "Mars/Olympus_Mons" is not a valid IANA time zone`))
+				Expect(session).To(gbytes.Say(`schedules.recurring_schedule\[0\].start_time: "25:00" does not match the format HH:mm`))
+				Expect(session).To(gbytes.Say(`schedules.recurring_schedule\[0\].days_of_week\[1\]: 8 must be between 1 and 7`))
+				Expect(session).To(gbytes.Say(`schedules.specific_date\[1\]: overlaps with schedules.specific_date\[0\]`))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Commands autoscaling-policy-diff, aspd", func() {
+
+		var urlpath = "/v1/apps/" + fakeAppID + "/policy"
+		Context("autoscaling-policy-diff", func() {
+
+			It("Require both APP_NAME and PATH_TO_POLICY_FILE as argument", func() {
+				args = []string{"autoscaling-policy-diff"}
+				session := runPluginCommand(ts, args...)
+
+				Expect(session).To(gbytes.Say("the required arguments `APP_NAME` and `PATH_TO_POLICY_FILE` were not provided"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			When("the app is found and the access token is correct", func() {
+				BeforeEach(func() {
+					setLoggedIn(rpcHandlers)
+					setTargeted(rpcHandlers)
+					rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+						*retVal = fakeAccessToken
+						return nil
+					}
+					apiServer.RouteToHandler("GET", "/v3/apps",
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`
+						{"resources":[{
+							"guid": "%s",
+							"name": "%s"}]}`, fakeAppID, fakeAppName)),
+					)
+					apiServer.RouteToHandler("GET", urlpath,
+						ghttp.CombineHandlers(
+							ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+							ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+						),
+					)
+				})
+
+				JustBeforeEach(func() {
+					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+					runPluginCommand(ts, args...)
+				})
+
+				It("Failed when policy file not exist", func() {
+					args = []string{"autoscaling-policy-diff", fakeAppName, outputFile}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.FailToLoadPolicyFile, outputFile))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Succeed when the policies only differ in order", func() {
+					policy := fakePolicy
+					policy.ScalingRules = []*ScalingRule{fakePolicy.ScalingRules[1], fakePolicy.ScalingRules[0]}
+					policyBytes, err := cjson.MarshalWithoutHTMLEscape(policy)
+					Expect(err).NotTo(HaveOccurred())
+					err = os.WriteFile(outputFile, policyBytes, 0666)
+					Expect(err).NotTo(HaveOccurred())
+
+					args = []string{"autoscaling-policy-diff", fakeAppName, outputFile}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session.Out).To(gbytes.Say(ui.DiffPolicyHint, fakeAppName, outputFile))
+					Expect(session.Out).To(gbytes.Say(ui.PolicyIdentical))
+					Expect(session.ExitCode()).To(Equal(0))
+				})
+
+				It("Print the differences and exit with 1 when the policies differ", func() {
+					policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
+					Expect(err).NotTo(HaveOccurred())
+					var policy ScalingPolicy
+					err = json.Unmarshal(policyBytes, &policy)
+					Expect(err).NotTo(HaveOccurred())
+					policy.InstanceMax = 4
+					policy.Schedules.RecurringSchedules[0].StartTime = "11:00"
+					policyBytes, err = cjson.MarshalWithoutHTMLEscape(policy)
+					Expect(err).NotTo(HaveOccurred())
+					err = os.WriteFile(outputFile, policyBytes, 0666)
+					Expect(err).NotTo(HaveOccurred())
+
+					args = []string{"autoscaling-policy-diff", fakeAppName, outputFile}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session.Out).To(gbytes.Say(ui.DiffPolicyHint, fakeAppName, outputFile))
+					Expect(session.Out).To(gbytes.Say(`~ instance_max_count: 2 -> 4`))
+					Expect(session.Out).To(gbytes.Say(`~ schedules.recurring_schedule\[0\]: start_time 10:00 -> 11:00`))
+					Expect(session.Out).To(gbytes.Say(`2 difference\(s\) found`))
+					Expect(session.Out).NotTo(gbytes.Say("FAILED"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Describe("Commands detach-autoscaling-policy, dasp", func() {
+
+		var urlpath = "/v1/apps/" + fakeAppID + "/policy"
+		Context("detach-autoscaling-policy", func() {
+
+			When("the args are not properly provided", func() {
+				It("Require APP_NAME as argument", func() {
+					args = []string{"detach-autoscaling-policy"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("required argument `APP_NAME` was not provided"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("cf api is not set ", func() {
+				BeforeEach(func() {
+
+					rpcHandlers.ApiEndpointStub = func(_ string, retVal *string) error {
+						*retVal = ""
+						return nil
+					}
+
+					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+					runPluginCommand(ts, args...)
+
+					rpcHandlers.ApiEndpointStub = func(_ string, retVal *string) error {
+						*retVal = ""
+						return nil
+					}
+				})
+
+				It("Failed with missing cf api setting", func() {
+					args = []string{"detach-autoscaling-policy", fakeAppName}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say(ui.NOCFAPIEndpoint))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("cf api is changed ", func() {
+				BeforeEach(func() {
+					urlConfig := []byte(fmt.Sprintf(`{"URL":"%s"}`, "autoscaler.bosh-lite.com"))
+					err = ioutil.WriteFile(api.ConfigFile(), urlConfig, 0600)
+					Expect(err).NotTo(HaveOccurred())
+
+				})
+
+				When("the default endpoint doesn't work", func() {
+
+					BeforeEach(func() {
+						apiServer.RouteToHandler("GET", "/health",
+							ghttp.RespondWith(http.StatusNotFound, ""),
+						)
+					})
+
+					It("Failed with no api endpoint setting", func() {
+						args = []string{"detach-autoscaling-policy", fakeAppName}
+						session := runPluginCommand(ts, args...)
+						Expect(session).To(gbytes.Say(ui.NoEndpoint))
+						Expect(session.ExitCode()).To(Equal(1))
+					})
+				})
+			})
+
+			When("cf not login", func() {
+				It("exits with 'You must be logged in' error ", func() {
+					args = []string{"detach-autoscaling-policy", fakeAppName}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say("You must be logged in"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("logged in to CF", func() {
+				BeforeEach(func() {
+
+					setLoggedIn(rpcHandlers)
 				})
 
 				When("not targeting a space", func() {
@@ -1180,70 +1797,693 @@ var _ = Describe("App-AutoScaler Commands", func() {
 							runPluginCommand(ts, args...)
 						})
 
-						When("access token is wrong", func() {
-							BeforeEach(func() {
-								apiServer.RouteToHandler("DELETE", urlpath,
-									ghttp.RespondWith(http.StatusUnauthorized, ""),
-								)
-							})
+						When("access token is wrong", func() {
+							BeforeEach(func() {
+								apiServer.RouteToHandler("DELETE", urlpath,
+									ghttp.RespondWith(http.StatusUnauthorized, ""),
+								)
+							})
+
+							It("failed with 401 error", func() {
+								args = []string{"detach-autoscaling-policy", fakeAppName}
+								session := runPluginCommand(ts, args...)
+
+								Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
+								Expect(session.ExitCode()).To(Equal(1))
+							})
+						})
+
+						When("access token is correct", func() {
+							BeforeEach(func() {
+								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+									*retVal = fakeAccessToken
+									return nil
+								}
+							})
+
+							When("policy not found", func() {
+								BeforeEach(func() {
+									apiServer.RouteToHandler("DELETE", urlpath,
+										ghttp.RespondWith(http.StatusNotFound, ""),
+									)
+								})
+
+								It("404 returned", func() {
+									args = []string{"detach-autoscaling-policy", fakeAppName}
+									session := runPluginCommand(ts, args...)
+									Expect(session.Out).To(gbytes.Say(ui.DetachPolicyHint, fakeAppName))
+									Expect(session).To(gbytes.Say(ui.PolicyNotFound, fakeAppName))
+									Expect(session.ExitCode()).To(Equal(1))
+
+								})
+							})
+
+							When("policy exist ", func() {
+								BeforeEach(func() {
+									apiServer.RouteToHandler("DELETE", urlpath,
+										ghttp.CombineHandlers(
+											ghttp.RespondWith(http.StatusOK, ""),
+											ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+										),
+									)
+								})
+
+								It("succeed", func() {
+
+									args = []string{"detach-autoscaling-policy", fakeAppName}
+									session := runPluginCommand(ts, args...)
+									Expect(session.Out).To(gbytes.Say(ui.DetachPolicyHint, fakeAppName))
+									Expect(session.Out).To(gbytes.Say("OK"))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+							})
+
+							When("running with --dry-run", func() {
+
+								It("shows the attached policy without detaching it", func() {
+									apiServer.RouteToHandler("GET", urlpath,
+										ghttp.CombineHandlers(
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+											ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+										),
+									)
+
+									args = []string{"detach-autoscaling-policy", fakeAppName, "--dry-run"}
+									session := runPluginCommand(ts, args...)
+									Expect(session.Out).To(gbytes.Say(`Detaching policy for app fakeAppName \(dry run\)...`))
+									Expect(session.Out).To(gbytes.Say(ui.DetachedPolicy))
+									Expect(session.Out).To(gbytes.Say(`"instance_max_count": 2`))
+									Expect(session.Out).To(gbytes.Say("OK"))
+									Expect(session.Out).To(gbytes.Say("TIP: This was a dry run"))
+									Expect(session.ExitCode()).To(Equal(0))
+
+									for _, req := range apiServer.ReceivedRequests() {
+										Expect(req.Method).NotTo(Equal("DELETE"))
+									}
+								})
+
+								It("fails when no policy is attached", func() {
+									apiServer.RouteToHandler("GET", urlpath,
+										ghttp.RespondWith(http.StatusNotFound, ""),
+									)
+
+									args = []string{"detach-autoscaling-policy", fakeAppName, "--dry-run"}
+									session := runPluginCommand(ts, args...)
+									Expect(session).To(gbytes.Say(ui.PolicyNotFound, fakeAppName))
+									Expect(session.ExitCode()).To(Equal(1))
+
+									for _, req := range apiServer.ReceivedRequests() {
+										Expect(req.Method).NotTo(Equal("DELETE"))
+									}
+								})
+							})
+
+						})
+
+					})
+				})
+			})
+		})
+	})
+
+	Describe("Commands edit-autoscaling-policy, easp", func() {
+
+		var (
+			urlpath   = "/v1/apps/" + fakeAppID + "/policy"
+			editorDir string
+			attached  []map[string]interface{}
+			// useEditor sets $EDITOR to a script running the given shell
+			// commands on the file $1, the script keeps a copy of each file it
+			// was opened with in $editorDir/opened
+			useEditor = func(commands string) {
+				script := filepath.Join(editorDir, "editor.sh")
+				Expect(os.WriteFile(script, []byte("#!/bin/sh\ncat \"$1\" >> "+filepath.Join(editorDir, "opened")+"\n"+commands+"\n"), 0755)).To(Succeed())
+				GinkgoT().Setenv("EDITOR", script)
+			}
+			opened = func() string {
+				contents, err := os.ReadFile(filepath.Join(editorDir, "opened"))
+				Expect(err).NotTo(HaveOccurred())
+				return string(contents)
+			}
+		)
+
+		It("Require APP_NAME as argument", func() {
+			session := runPluginCommand(ts, "edit-autoscaling-policy")
+
+			Expect(session).To(gbytes.Say("the required argument `APP_NAME` was not provided"))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		When("the app is found and the access token is correct", func() {
+			BeforeEach(func() {
+				editorDir = GinkgoT().TempDir()
+				attached = nil
+				setLoggedIn(rpcHandlers)
+				setTargeted(rpcHandlers)
+				rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+					*retVal = fakeAccessToken
+					return nil
+				}
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName)),
+				)
+				apiServer.RouteToHandler("GET", urlpath,
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+				)
+				apiServer.RouteToHandler("PUT", urlpath,
+					func(w http.ResponseWriter, req *http.Request) {
+						var policy map[string]interface{}
+						Expect(json.NewDecoder(req.Body).Decode(&policy)).To(Succeed())
+						attached = append(attached, policy)
+						// the first attempt to raise instance_max_count to 4 is rejected
+						if len(attached) == 1 && policy["instance_max_count"] == float64(4) {
+							ghttp.RespondWith(http.StatusBadRequest, `[{"context":"(root).instance_max_count","description":"quota exceeded"}]`)(w, req)
+							return
+						}
+						ghttp.RespondWith(http.StatusOK, "")(w, req)
+					},
+				)
+			})
+
+			JustBeforeEach(func() {
+				runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+			})
+
+			It("attaches the edited policy once confirmed", func() {
+				useEditor(`sed -i 's/instance_min_count: 1/instance_min_count: 2/' "$1"`)
+				session := runPluginCommandWithStdin(ts, strings.NewReader("y\n"), "edit-autoscaling-policy", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(ui.EditPolicyHint, fakeAppName))
+				Expect(session.Out).To(gbytes.Say(`~ instance_min_count: 1 -> 2`))
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ConfirmAttachPolicy, fakeAppName))))
+				Expect(session.Out).To(gbytes.Say(ui.OK))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(opened()).To(ContainSubstring("# Please edit the policy of app " + fakeAppName))
+				Expect(attached).To(HaveLen(1))
+				Expect(attached[0]).To(HaveKeyWithValue("instance_min_count", float64(2)))
+			})
+
+			It("edits the policy as JSON", func() {
+				useEditor(`sed -i 's/"instance_min_count": 1/"instance_min_count": 2/' "$1"`)
+				session := runPluginCommandWithStdin(ts, strings.NewReader("y\n"), "edit-autoscaling-policy", fakeAppName, "--format", "json")
+
+				Expect(session.Out).To(gbytes.Say(`~ instance_min_count: 1 -> 2`))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(attached).To(HaveLen(1))
+			})
+
+			It("does not attach the policy without confirmation", func() {
+				useEditor(`sed -i 's/instance_min_count: 1/instance_min_count: 2/' "$1"`)
+				session := runPluginCommandWithStdin(ts, strings.NewReader("n\n"), "edit-autoscaling-policy", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(ui.EditPolicyCancelled))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(attached).To(BeEmpty())
+			})
+
+			It("cancels the edit when the policy is left unchanged", func() {
+				useEditor(`true`)
+				session := runPluginCommand(ts, "edit-autoscaling-policy", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(ui.EditPolicyCancelled))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(attached).To(BeEmpty())
+			})
+
+			It("reopens the editor with the validation errors", func() {
+				useEditor(`sed -i 's/instance_min_count: 1$/instance_min_count: 10/' "$1"`)
+				session := runPluginCommand(ts, "edit-autoscaling-policy", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(ui.EditPolicyCancelled))
+				Expect(session).To(gbytes.Say("Invalid policy definition"))
+				Expect(session.ExitCode()).To(Equal(1))
+				Expect(opened()).To(MatchRegexp(`(?m)^# Invalid policy definition: $`))
+				Expect(opened()).To(MatchRegexp(`(?m)^# .*instance_min_count`))
+				Expect(attached).To(BeEmpty())
+			})
+
+			It("reopens the editor with the error of the AutoScaler API", func() {
+				useEditor(`if grep -q "quota exceeded" "$1"; then
+	sed -i 's/instance_max_count: 4/instance_max_count: 3/' "$1"
+else
+	sed -i 's/instance_max_count: 2/instance_max_count: 4/' "$1"
+fi`)
+				session := runPluginCommandWithStdin(ts, strings.NewReader("y\ny\n"), "edit-autoscaling-policy", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(`~ instance_max_count: 2 -> 4`))
+				Expect(session.Out).To(gbytes.Say(`quota exceeded`))
+				Expect(session.Out).To(gbytes.Say(`~ instance_max_count: 2 -> 3`))
+				Expect(session.Out).To(gbytes.Say(ui.OK))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(opened()).To(ContainSubstring("# (root).instance_max_count: quota exceeded"))
+				Expect(attached).To(HaveLen(2))
+				Expect(attached[1]).To(HaveKeyWithValue("instance_max_count", float64(3)))
+			})
+		})
+	})
+
+	Describe("Commands export-autoscaling-policies, exasp and import-autoscaling-policies, imasp", func() {
+
+		const otherAppID, otherAppName = "otherAppId", "otherAppName"
+		var dir string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			setLoggedIn(rpcHandlers)
+			setTargeted(rpcHandlers)
+			apiServer.RouteToHandler("GET", "/v3/apps",
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("space_guids", "fakeSpaceGuid"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[
+						{"guid": "%s", "name": "%s"},
+						{"guid": "%s", "name": "%s"}]}`, otherAppID, otherAppName, fakeAppID, fakeAppName)),
+				),
+			)
+			apiServer.RouteToHandler("GET", "/v3/processes",
+				ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+			)
+		})
+
+		JustBeforeEach(func() {
+			runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+		})
+
+		Context("export-autoscaling-policies", func() {
+
+			BeforeEach(func() {
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/"+otherAppID+"/policy",
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				)
+			})
+
+			It("writes one file per app with a policy and an index", func() {
+				session := runPluginCommand(ts, "export-autoscaling-policies", "--dir", dir)
+
+				Expect(session.Out).To(gbytes.Say(ui.ExportPoliciesHint, "fakeSpace", dir))
+				Expect(session.Out).To(gbytes.Say("OK"))
+				Expect(session.Out).To(gbytes.Say(`App Name\s+Status\s+Details`))
+				Expect(session.Out).To(gbytes.Say(fakeAppName + `\s+exported\s+` + fakeAppName + `\.json`))
+				Expect(session.Out).To(gbytes.Say(otherAppName + `\s+no policy`))
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ExportPoliciesSummary, 1, filepath.Join(dir, "index.json"), 1, 0))))
+				Expect(session.ExitCode()).To(Equal(0))
+
+				var policy ScalingPolicy
+				contents, err := os.ReadFile(filepath.Join(dir, fakeAppName+".json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &policy)).To(Succeed())
+				Expect(policy).To(Equal(fakePolicy))
+
+				var index struct {
+					Space    string `json:"space"`
+					Policies []struct {
+						AppName string `json:"app_name"`
+						AppGUID string `json:"app_guid"`
+						File    string `json:"file"`
+					} `json:"policies"`
+				}
+				contents, err = os.ReadFile(filepath.Join(dir, "index.json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &index)).To(Succeed())
+				Expect(index.Space).To(Equal("fakeSpace"))
+				Expect(index.Policies).To(HaveLen(1))
+				Expect(index.Policies[0].AppName).To(Equal(fakeAppName))
+				Expect(index.Policies[0].AppGUID).To(Equal(fakeAppID))
+				Expect(index.Policies[0].File).To(Equal(fakeAppName + ".json"))
+			})
+
+			It("continues past apps failing to export", func() {
+				apiServer.RouteToHandler("GET", "/v1/apps/"+otherAppID+"/policy",
+					ghttp.RespondWith(http.StatusInternalServerError, `{"error": "internal error"}`),
+				)
+				session := runPluginCommand(ts, "export-autoscaling-policies", "--dir", dir)
+
+				Expect(session.Out).To(gbytes.Say(fakeAppName + `\s+exported`))
+				Expect(session.Out).To(gbytes.Say(otherAppName + `\s+failed\s+internal error`))
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ExportPoliciesSummary, 1, filepath.Join(dir, "index.json"), 0, 1))))
+				Expect(session.ExitCode()).To(Equal(1))
+				Expect(filepath.Join(dir, fakeAppName+".json")).To(BeARegularFile())
+			})
+
+			It("writes a separate file for each app whose name maps to the same file", func() {
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, `{"resources":[
+						{"guid": "guid1", "name": "a/b"},
+						{"guid": "guid2", "name": "a_b"},
+						{"guid": "guid3", "name": "A_B"}]}`),
+				)
+				for _, appID := range []string{"guid1", "guid2", "guid3"} {
+					apiServer.RouteToHandler("GET", "/v1/apps/"+appID+"/policy",
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"instance_min_count": 1, "instance_max_count": 2, "scaling_rules": [{"metric_type": "%s", "threshold": 1, "operator": ">", "adjustment": "+1"}]}`, appID)),
+					)
+				}
+				var healthChecks int32
+				apiServer.RouteToHandler("GET", "/health",
+					func(w http.ResponseWriter, req *http.Request) {
+						atomic.AddInt32(&healthChecks, 1)
+						ghttp.RespondWith(http.StatusOK, "")(w, req)
+					},
+				)
+				session := runPluginCommand(ts, "export-autoscaling-policies", "--dir", dir)
+
+				Expect(session.Out).To(gbytes.Say(`A_B\s+exported\s+A_B\.json`))
+				Expect(session.Out).To(gbytes.Say(`a/b\s+exported\s+a_b-2\.json`))
+				Expect(session.Out).To(gbytes.Say(`a_b\s+exported\s+a_b-3\.json`))
+				Expect(session.ExitCode()).To(Equal(0))
+				for file, appID := range map[string]string{"A_B.json": "guid3", "a_b-2.json": "guid1", "a_b-3.json": "guid2"} {
+					Expect(os.ReadFile(filepath.Join(dir, file))).To(ContainSubstring(appID))
+				}
+				Expect(atomic.LoadInt32(&healthChecks)).To(Equal(int32(1)))
+			})
+
+			It("does not overwrite the policy of an app named like the index", func() {
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, `{"resources":[{"guid": "guid1", "name": "Index"}]}`),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/guid1/policy",
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+				)
+				session := runPluginCommand(ts, "export-autoscaling-policies", "--dir", dir)
+
+				Expect(session.Out).To(gbytes.Say(`Index\s+exported\s+Index-2\.json`))
+				Expect(session.ExitCode()).To(Equal(0))
+				var policy ScalingPolicy
+				contents, err := os.ReadFile(filepath.Join(dir, "Index-2.json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &policy)).To(Succeed())
+				Expect(policy).To(Equal(fakePolicy))
+				Expect(os.ReadFile(filepath.Join(dir, "index.json"))).To(ContainSubstring(`"file": "Index-2.json"`))
+			})
+		})
+
+		Context("import-autoscaling-policies", func() {
+
+			var attached []string
+
+			BeforeEach(func() {
+				attached = nil
+				policyBytes, err := cjson.MarshalWithoutHTMLEscape(fakePolicy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(filepath.Join(dir, "a.json"), policyBytes, 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"instance_min_count": 1}`), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "index.json"), []byte(fmt.Sprintf(`{"space": "staging", "policies": [
+					{"app_name": "%s", "app_guid": "stagingGuid1", "file": "a.json"},
+					{"app_name": "%s", "app_guid": "stagingGuid2", "file": "b.json"},
+					{"app_name": "missingApp", "app_guid": "stagingGuid3", "file": "a.json"}]}`, fakeAppName, otherAppName)), 0644)).To(Succeed())
+
+				for _, appID := range []string{fakeAppID, otherAppID} {
+					appID := appID
+					apiServer.RouteToHandler("PUT", "/v1/apps/"+appID+"/policy",
+						func(w http.ResponseWriter, req *http.Request) {
+							attached = append(attached, appID)
+							ghttp.RespondWith(http.StatusOK, "")(w, req)
+						},
+					)
+				}
+			})
+
+			It("Require DIR as argument", func() {
+				session := runPluginCommand(ts, "import-autoscaling-policies")
+
+				Expect(session).To(gbytes.Say("the required argument `DIR` was not provided"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("Failed when the index does not exist", func() {
+				session := runPluginCommand(ts, "import-autoscaling-policies", filepath.Join(dir, "missing"))
+
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.FailToLoadPolicyIndex, filepath.Join(dir, "missing", "index.json")))))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("Failed when the index points outside of the directory", func() {
+				Expect(os.WriteFile(filepath.Join(dir, "index.json"), []byte(fmt.Sprintf(`{"space": "staging", "policies": [
+					{"app_name": "%s", "app_guid": "stagingGuid1", "file": "../a.json"}]}`, fakeAppName)), 0644)).To(Succeed())
+				session := runPluginCommand(ts, "import-autoscaling-policies", dir)
+
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.InvalidPolicyIndex, filepath.Join(dir, "index.json"), fmt.Sprintf(ui.PolicyFileOutsideDir, "../a.json")))))
+				Expect(session.ExitCode()).To(Equal(1))
+				Expect(attached).To(BeEmpty())
+			})
+
+			It("attaches the policies by app name and reports each app", func() {
+				session := runPluginCommand(ts, "import-autoscaling-policies", dir)
+
+				Expect(session.Out).To(gbytes.Say(ui.ImportPoliciesHint, dir, "fakeSpace"))
+				Expect(session.Out).To(gbytes.Say(fakeAppName + `\s+imported\s+a\.json`))
+				Expect(session.Out).To(gbytes.Say(otherAppName + `\s+failed\s+Invalid policy definition`))
+				Expect(session.Out).To(gbytes.Say(`missingApp\s+failed\s+` + regexp.QuoteMeta(fmt.Sprintf(ui.NoApp, "missingApp"))))
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ImportPoliciesSummary, 1, 2))))
+				Expect(session.ExitCode()).To(Equal(1))
+				Expect(attached).To(Equal([]string{fakeAppID}))
+			})
+
+			It("checks the health of the AutoScaler API once", func() {
+				var healthChecks int32
+				apiServer.RouteToHandler("GET", "/health",
+					func(w http.ResponseWriter, req *http.Request) {
+						atomic.AddInt32(&healthChecks, 1)
+						ghttp.RespondWith(http.StatusOK, "")(w, req)
+					},
+				)
+				Expect(os.WriteFile(filepath.Join(dir, "index.json"), []byte(fmt.Sprintf(`{"space": "staging", "policies": [
+					{"app_name": "%s", "app_guid": "stagingGuid1", "file": "a.json"},
+					{"app_name": "%s", "app_guid": "stagingGuid2", "file": "a.json"}]}`, fakeAppName, otherAppName)), 0644)).To(Succeed())
+				session := runPluginCommand(ts, "import-autoscaling-policies", dir)
+
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ImportPoliciesSummary, 2, 0))))
+				Expect(session.ExitCode()).To(Equal(0))
+				Expect(attached).To(ConsistOf(fakeAppID, otherAppID))
+				Expect(atomic.LoadInt32(&healthChecks)).To(Equal(int32(1)))
+			})
+		})
+	})
+
+	Describe("Commands create-autoscaling-credential, casc", func() {
+
+		var urlpath = "/v1/apps/" + fakeAppID + "/credential"
+		Context("create-autoscaling-credential", func() {
+
+			When("the args are not properly provided", func() {
+				It("Require APP_NAME as argument", func() {
+					args = []string{"create-autoscaling-credential"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("required argument `APP_NAME` was not provided"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("not logged into CF", func() {
+				It("exits with 'You must be logged in' error ", func() {
+					args = []string{"create-autoscaling-credential", fakeAppName}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say("You must be logged in"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("the app is found", func() {
+				BeforeEach(func() {
+					setLoggedIn(rpcHandlers)
+					setTargeted(rpcHandlers)
+					apiServer.RouteToHandler("GET", "/v3/apps",
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`
+						{"resources":[{
+							"guid": "%s",
+							"name": "%s"}]}`, fakeAppID, fakeAppName)),
+					)
+				})
+
+				JustBeforeEach(func() {
+					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+					runPluginCommand(ts, args...)
+				})
+
+				When("access token is wrong", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("PUT", urlpath,
+							ghttp.RespondWith(http.StatusUnauthorized, ""),
+						)
+					})
+
+					It("failed with 401 error", func() {
+						args = []string{"create-autoscaling-credential", fakeAppName}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
+						Expect(session.ExitCode()).To(Equal(1))
+					})
+
+					It("keeps an existing output file", func() {
+						Expect(os.WriteFile(outputFile, []byte(`{"username":"old-user"}`), 0600)).To(Succeed())
+						args = []string{"create-autoscaling-credential", fakeAppName, "--output", outputFile}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.ExitCode()).To(Equal(1))
+						Expect(os.ReadFile(outputFile)).To(MatchJSON(`{"username":"old-user"}`))
+						Expect(filepath.Glob(filepath.Join(filepath.Dir(outputFile), "."+filepath.Base(outputFile)+"-*"))).To(BeEmpty())
+					})
+				})
+
+				When("a random credential is requested", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("PUT", urlpath,
+							ghttp.CombineHandlers(
+								ghttp.RespondWith(http.StatusOK, `{"app_id":"fakeAppId","username":"random-user","password":"random-pass","url":"https://autoscalermetrics.example.com"}`),
+								ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+							),
+						)
+					})
+
+					It("Succeed to print the credential to stdout", func() {
+						args = []string{"create-autoscaling-credential", fakeAppName}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(ui.CreateCredentialHint, fakeAppName))
+						Expect(session.Out).To(gbytes.Say(`"username": "random-user"`))
+						Expect(session.Out).To(gbytes.Say(`"password": "random-pass"`))
+						Expect(session.Out).To(gbytes.Say(ui.CreateCredentialWarning, fakeAppName))
+						Expect(session.ExitCode()).To(Equal(0))
+					})
+
+					It("Succeed to print the credential to file", func() {
+						args = []string{"create-autoscaling-credential", fakeAppName, "--output", outputFile}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(ui.SaveCredentialHint, fakeAppName, outputFile))
+						Expect(session.Out).To(gbytes.Say("OK"))
+						Expect(session.ExitCode()).To(Equal(0))
+
+						contents, err := os.ReadFile(outputFile)
+						Expect(err).NotTo(HaveOccurred())
+						var actualCredential CredentialResponse
+						err = json.Unmarshal(contents, &actualCredential)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(actualCredential.Username).To(Equal("random-user"))
+						Expect(actualCredential.Password).To(Equal("random-pass"))
+					})
+				})
+
+				When("a user-defined credential is provided", func() {
+					var credentialFile = "credential.json"
+
+					BeforeEach(func() {
+						apiServer.RouteToHandler("PUT", urlpath,
+							ghttp.CombineHandlers(
+								ghttp.VerifyJSON(`{"username":"user","password":"pass"}`),
+								ghttp.RespondWith(http.StatusOK, `{"app_id":"fakeAppId","username":"user","password":"pass","url":"https://autoscalermetrics.example.com"}`),
+							),
+						)
+					})
+
+					AfterEach(func() {
+						os.Remove(credentialFile)
+					})
+
+					It("Succeed with a valid credential file", func() {
+						err = os.WriteFile(credentialFile, []byte(`{"username":"user","password":"pass"}`), 0600)
+						Expect(err).NotTo(HaveOccurred())
+
+						args = []string{"create-autoscaling-credential", fakeAppName, "--credential-file", credentialFile}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(`"username": "user"`))
+						Expect(session.ExitCode()).To(Equal(0))
+					})
+
+					It("Failed when the credential file does not exist", func() {
+						args = []string{"create-autoscaling-credential", fakeAppName, "--credential-file", credentialFile}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session).To(gbytes.Say(ui.FailToLoadCredentialFile, credentialFile))
+						Expect(session.ExitCode()).To(Equal(1))
+					})
+
+					It("Failed when the password is missing", func() {
+						err = os.WriteFile(credentialFile, []byte(`{"username":"user"}`), 0600)
+						Expect(err).NotTo(HaveOccurred())
+
+						args = []string{"create-autoscaling-credential", fakeAppName, "--credential-file", credentialFile}
+						session := runPluginCommand(ts, args...)
 
-							It("failed with 401 error", func() {
-								args = []string{"detach-autoscaling-policy", fakeAppName}
-								session := runPluginCommand(ts, args...)
+						Expect(session).To(gbytes.Say(strings.TrimSuffix(ui.InvalidCredential, "%v.")))
+						Expect(session.ExitCode()).To(Equal(1))
+					})
+				})
+			})
+		})
+	})
 
-								Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
-								Expect(session.ExitCode()).To(Equal(1))
-							})
-						})
+	Describe("Commands delete-autoscaling-credential, dasc", func() {
 
-						When("access token is correct", func() {
-							BeforeEach(func() {
-								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
-									*retVal = fakeAccessToken
-									return nil
-								}
-							})
+		var urlpath = "/v1/apps/" + fakeAppID + "/credential"
+		Context("delete-autoscaling-credential", func() {
 
-							When("policy not found", func() {
-								BeforeEach(func() {
-									apiServer.RouteToHandler("DELETE", urlpath,
-										ghttp.RespondWith(http.StatusNotFound, ""),
-									)
-								})
+			When("the args are not properly provided", func() {
+				It("Require APP_NAME as argument", func() {
+					args = []string{"delete-autoscaling-credential"}
+					session := runPluginCommand(ts, args...)
 
-								It("404 returned", func() {
-									args = []string{"detach-autoscaling-policy", fakeAppName}
-									session := runPluginCommand(ts, args...)
-									Expect(session.Out).To(gbytes.Say(ui.DetachPolicyHint, fakeAppName))
-									Expect(session).To(gbytes.Say(ui.PolicyNotFound, fakeAppName))
-									Expect(session.ExitCode()).To(Equal(1))
+					Expect(session).To(gbytes.Say("required argument `APP_NAME` was not provided"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
 
-								})
-							})
+			When("the app is found", func() {
+				BeforeEach(func() {
+					setLoggedIn(rpcHandlers)
+					setTargeted(rpcHandlers)
+					apiServer.RouteToHandler("GET", "/v3/apps",
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`
+						{"resources":[{
+							"guid": "%s",
+							"name": "%s"}]}`, fakeAppID, fakeAppName)),
+					)
+				})
 
-							When("policy exist ", func() {
-								BeforeEach(func() {
-									apiServer.RouteToHandler("DELETE", urlpath,
-										ghttp.CombineHandlers(
-											ghttp.RespondWith(http.StatusOK, ""),
-											ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
-										),
-									)
-								})
+				JustBeforeEach(func() {
+					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+					runPluginCommand(ts, args...)
+				})
 
-								It("succeed", func() {
+				When("access token is wrong", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("DELETE", urlpath,
+							ghttp.RespondWith(http.StatusUnauthorized, ""),
+						)
+					})
 
-									args = []string{"detach-autoscaling-policy", fakeAppName}
-									session := runPluginCommand(ts, args...)
-									Expect(session.Out).To(gbytes.Say(ui.DetachPolicyHint, fakeAppName))
-									Expect(session.Out).To(gbytes.Say("OK"))
-									Expect(session.ExitCode()).To(Equal(0))
-								})
+					It("failed with 401 error", func() {
+						args = []string{"delete-autoscaling-credential", fakeAppName}
+						session := runPluginCommand(ts, args...)
 
-							})
+						Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
+						Expect(session.ExitCode()).To(Equal(1))
+					})
+				})
 
-						})
+				When("the credential exists", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("DELETE", urlpath,
+							ghttp.CombineHandlers(
+								ghttp.RespondWith(http.StatusOK, ""),
+								ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+							),
+						)
+					})
 
+					It("succeed", func() {
+						args = []string{"delete-autoscaling-credential", fakeAppName}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(ui.DeleteCredentialHint, fakeAppName))
+						Expect(session.Out).To(gbytes.Say("OK"))
+						Expect(session.Out).To(gbytes.Say(ui.DeleteCredentialWarning, fakeAppName))
+						Expect(session.ExitCode()).To(Equal(0))
 					})
 				})
 			})
@@ -1279,6 +2519,14 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					Expect(session.ExitCode()).To(Equal(1))
 				})
 
+				It("Failed when METRIC_NAME is used with --all-policy-metrics", func() {
+					args = []string{"autoscaling-metrics", fakeAppName, metricName, "--all-policy-metrics"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.AllPolicyWithMetric))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
 				It("Failed when METRIC_NAME is unsupported", func() {
 					args = []string{"autoscaling-metrics", fakeAppName, "invalid-metric-name%"}
 					session := runPluginCommand(ts, args...)
@@ -1343,6 +2591,48 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					Expect(session.ExitCode()).To(Equal(1))
 				})
 
+				It("Failed when --chart is used with --watch or a machine-readable format", func() {
+					session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--chart", "--watch")
+					Expect(session).To(gbytes.Say(ui.ChartWithWatch))
+					Expect(session.ExitCode()).To(Equal(1))
+
+					session = runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--chart=sparkline", "--format", "json")
+					Expect(session).To(gbytes.Say(ui.ChartWithFormat))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --summary is used with --watch", func() {
+					args = []string{"autoscaling-metrics", fakeAppName, metricName, "--summary", "--watch"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.SummaryWithWatch))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --watch is used with --end", func() {
+					args = []string{"autoscaling-metrics", fakeAppName, metricName, "--watch", "--end", "2018-01-01T00:00:00Z"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.WatchWithEndTime))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --watch is used with a machine-readable format", func() {
+					args = []string{"autoscaling-metrics", fakeAppName, metricName, "-w", "--format", "csv"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.WatchWithFormat))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when the poll interval is not positive", func() {
+					args = []string{"autoscaling-metrics", fakeAppName, metricName, "--watch", "--interval", "-1s"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Invalid poll interval -1s"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
 				It("Failed when output file path is invalid", func() {
 					args = []string{"autoscaling-metrics", fakeAppName, metricName, "--output", "invalidDir/invalidFile"}
 					session := runPluginCommand(ts, args...)
@@ -1429,67 +2719,409 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					})
 				})
 
-				When("targeting a space", func() {
-					BeforeEach(func() {
-						setTargeted(rpcHandlers)
-					})
+				When("targeting a space", func() {
+					BeforeEach(func() {
+						setTargeted(rpcHandlers)
+					})
+
+					When("app not found", func() {
+						BeforeEach(func() {
+							apiServer.RouteToHandler("GET", "/v3/apps",
+								ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+							)
+						})
+
+						It("exits with 'App not found' error ", func() {
+							args = []string{"autoscaling-metrics", fakeAppName, metricName}
+							session := runPluginCommand(ts, args...)
+							Expect(session).To(gbytes.Say("App 'fakeAppName' not found."))
+							Expect(session.ExitCode()).To(Equal(1))
+						})
+					})
+
+					When("the app is found", func() {
+						BeforeEach(func() {
+							apiServer.RouteToHandler("GET", "/v3/apps",
+								ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`
+						{"resources":[{
+							"guid": "%s",
+							"name": "%s"}]}`, fakeAppID, fakeAppName)),
+							)
+						})
+
+						JustBeforeEach(func() {
+							args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+							runPluginCommand(ts, args...)
+						})
+
+						When("access token is wrong", func() {
+							BeforeEach(func() {
+								apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
+									ghttp.CombineHandlers(
+										ghttp.RespondWith(http.StatusUnauthorized, ""),
+									),
+								)
+							})
+
+							It("failed with 401 error", func() {
+								args = []string{"autoscaling-metrics", fakeAppName, metricName}
+								session := runPluginCommand(ts, args...)
+
+								Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
+								Expect(session.ExitCode()).To(Equal(1))
+							})
+						})
+
+						When("access token is correct", func() {
+
+							BeforeEach(func() {
+								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+									*retVal = fakeAccessToken
+									return nil
+								}
+							})
+							When("charting the metrics", func() {
+								BeforeEach(func() {
+									var metrics []*AppAggregatedMetric
+									for i := 0; i < 20; i++ {
+										metrics = append(metrics, &AppAggregatedMetric{
+											AppId:     fakeAppID,
+											Name:      metricName,
+											Unit:      "MB",
+											Value:     strconv.Itoa(40 + i*5),
+											Timestamp: time.Date(2026, 1, 1, 0, i, 0, 0, time.UTC).UnixNano(),
+										})
+									}
+									apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
+										ghttp.CombineHandlers(
+											ghttp.VerifyFormKV("order", "asc"),
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+												TotalResults: 20,
+												TotalPages:   1,
+												Page:         1,
+												Metrics:      metrics,
+											}),
+										),
+									)
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+										ghttp.RespondWith(http.StatusOK, `{
+											"instance_min_count": 1,
+											"instance_max_count": 5,
+											"scaling_rules": [
+												{"metric_type": "memoryused", "threshold": 30, "operator": "<", "adjustment": "-1"},
+												{"metric_type": "memoryused", "threshold": 120, "operator": ">=", "adjustment": "+1"},
+												{"metric_type": "cpu", "threshold": 10, "operator": ">", "adjustment": "+1"}
+											]}`),
+									)
+								})
+
+								It("draws a line chart with the thresholds of the metric", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--chart", "--timezone", "UTC")
+
+									Expect(session.Out).To(gbytes.Say(`135MB \| +\*+\n`))
+									Expect(session.Out).To(gbytes.Say(`115.91MB \|-+\*+-+\n`))
+									Expect(session.Out).To(gbytes.Say(`39.55MB \|\*+ +\n`))
+									Expect(session.Out).To(gbytes.Say(`30MB \|-+\n`))
+									Expect(session.Out).To(gbytes.Say(` +\+-+\n`))
+									Expect(session.Out).To(gbytes.Say(`2026-01-01T00:00:00Z +2026-01-01T00:19:00Z\n`))
+									Expect(session.Out).To(gbytes.Say(`--- memoryused < 30MB \(-1\)\n--- memoryused >= 120MB \(\+1\)\n`))
+									Expect(string(session.Out.Contents())).NotTo(ContainSubstring("cpu"))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("draws a sparkline", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--chart=sparkline", "--timezone", "UTC")
+
+									Expect(session.Out).To(gbytes.Say(`▁+.*█\n`))
+									Expect(session.Out).To(gbytes.Say(`min 40MB, max 135MB, 2026-01-01T00:00:00Z - 2026-01-01T00:19:00Z`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("writes the plain table to the output file", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--chart", "--output", outputFile)
+
+									Expect(session.Out).To(gbytes.Say(`39.55MB \|\*`))
+									Expect(session.Out).To(gbytes.Say("OK"))
+									content, err := os.ReadFile(outputFile)
+									Expect(err).NotTo(HaveOccurred())
+									Expect(string(content)).To(HavePrefix("Metrics Name"))
+									Expect(strings.Count(string(content), "memoryused")).To(Equal(20))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+							})
+
+							When("summarizing the metrics", func() {
+								BeforeEach(func() {
+									var metrics []*AppAggregatedMetric
+									for i := 1; i <= 10; i++ {
+										metrics = append(metrics, &AppAggregatedMetric{
+											AppId:     fakeAppID,
+											Name:      metricName,
+											Unit:      "MB",
+											Value:     strconv.Itoa(i),
+											Timestamp: now.UnixNano() + int64(i*30*1e9),
+										})
+									}
+									apiServer.AppendHandlers(
+										ghttp.CombineHandlers(
+											ghttp.VerifyFormKV("page", "1"),
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+												TotalResults: 10,
+												TotalPages:   2,
+												Page:         1,
+												Metrics:      metrics[0:5],
+											}),
+										),
+										ghttp.CombineHandlers(
+											ghttp.VerifyFormKV("page", "2"),
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+												TotalResults: 10,
+												TotalPages:   2,
+												Page:         2,
+												Metrics:      metrics[5:10],
+											}),
+										),
+									)
+								})
+
+								It("prints the statistics of all pages with units", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--summary")
+
+									Expect(session.Out).To(gbytes.Say(fmt.Sprintf(ui.SummarizeAggregatedMetricsHint, metricName, fakeAppName)))
+									Expect(session.Out).To(gbytes.Say(`Metrics Name\s+Count\s+Min\s+Max\s+Mean\s+Median\s+P90\s+P95\s+P99\s+Std Dev`))
+									Expect(session.Out).To(gbytes.Say(`memoryused\s+10\s+1MB\s+10MB\s+5.5MB\s+5.5MB\s+9.1MB\s+9.55MB\s+9.91MB\s+2.87MB`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("exports the statistics", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName, "--summary", "--format", "csv")
+
+									rows := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+									Expect(rows).To(Equal([]string{
+										"name,unit,count,min,max,mean,median,p90,p95,p99,stddev",
+										"memoryused,MB,10,1,10,5.5,5.5,9.1,9.55,9.91,2.87",
+									}))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+							})
+
+							When("querying multiple metrics", func() {
+								var (
+									startTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+									respond   = func(name, unit string, values map[int]string) http.HandlerFunc {
+										var metrics []*AppAggregatedMetric
+										for minutes := 0; minutes < 3; minutes++ {
+											if value, ok := values[minutes]; ok {
+												metrics = append(metrics, &AppAggregatedMetric{
+													AppId:     fakeAppID,
+													Name:      name,
+													Unit:      unit,
+													Value:     value,
+													Timestamp: startTime.Add(time.Duration(minutes) * time.Minute).UnixNano(),
+												})
+											}
+										}
+										return ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+											TotalResults: uint32(len(metrics)),
+											TotalPages:   1,
+											Page:         1,
+											Metrics:      metrics,
+										})
+									}
+								)
+
+								BeforeEach(func() {
+									apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
+										respond(metricName, "MB", map[int]string{0: "100", 1: "120", 2: "90"}))
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/aggregated_metric_histories/cpu",
+										respond("cpu", "%", map[int]string{0: "12", 2: "30"}))
+								})
+
+								It("prints the metrics aligned by timestamp", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName+",cpu", "--timezone", "UTC")
+
+									Expect(session.Out).To(gbytes.Say(fmt.Sprintf(ui.ShowAggregatedMetricsHint, metricName+", cpu", fakeAppName)))
+									Expect(session.Out).To(gbytes.Say(`Timestamp\s+memoryused\s+cpu`))
+									Expect(session.Out).To(gbytes.Say(`2026-01-01T00:02:00Z\s+90MB\s+30%`))
+									Expect(session.Out).To(gbytes.Say(`2026-01-01T00:01:00Z\s+120MB\s+-`))
+									Expect(session.Out).To(gbytes.Say(`2026-01-01T00:00:00Z\s+100MB\s+12%`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("exports the metrics with one column per metric", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, "cpu, "+metricName, "--asc", "--format", "csv")
+
+									rows := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+									Expect(rows).To(Equal([]string{
+										"timestamp,cpu,memoryused",
+										fmt.Sprintf("%d,12,100", startTime.UnixNano()),
+										fmt.Sprintf("%d,,120", startTime.Add(time.Minute).UnixNano()),
+										fmt.Sprintf("%d,30,90", startTime.Add(2*time.Minute).UnixNano()),
+									}))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("retrieves the metric types of the policy", func() {
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+										ghttp.RespondWith(http.StatusOK, `{
+											"instance_min_count": 1,
+											"instance_max_count": 5,
+											"scaling_rules": [
+												{"metric_type": "cpu", "threshold": 80, "operator": ">=", "adjustment": "+1"},
+												{"metric_type": "memoryused", "threshold": 30, "operator": "<", "adjustment": "-1"},
+												{"metric_type": "cpu", "threshold": 10, "operator": "<", "adjustment": "-1"}
+											]}`),
+									)
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, "--all-policy-metrics", "--asc", "--format", "json")
+
+									var records []map[string]interface{}
+									Expect(json.Unmarshal(session.Out.Contents(), &records)).To(Succeed())
+									Expect(records).To(HaveLen(3))
+									Expect(records[0]["metrics"]).To(Equal(map[string]interface{}{
+										"cpu":        map[string]interface{}{"value": "12", "unit": "%"},
+										"memoryused": map[string]interface{}{"value": "100", "unit": "MB"},
+									}))
+									Expect(records[1]["metrics"]).To(HaveLen(1))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
 
-					When("app not found", func() {
-						BeforeEach(func() {
-							apiServer.RouteToHandler("GET", "/v3/apps",
-								ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
-							)
-						})
+								It("summarizes every metric", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName+",cpu", "--summary")
 
-						It("exits with 'App not found' error ", func() {
-							args = []string{"autoscaling-metrics", fakeAppName, metricName}
-							session := runPluginCommand(ts, args...)
-							Expect(session).To(gbytes.Say("App 'fakeAppName' not found."))
-							Expect(session.ExitCode()).To(Equal(1))
-						})
-					})
+									Expect(session.Out).To(gbytes.Say(`memoryused\s+3\s+90MB\s+120MB`))
+									Expect(session.Out).To(gbytes.Say(`cpu\s+2\s+12%\s+30%`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
 
-					When("the app is found", func() {
-						BeforeEach(func() {
-							apiServer.RouteToHandler("GET", "/v3/apps",
-								ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`
-						{"resources":[{
-							"guid": "%s",
-							"name": "%s"}]}`, fakeAppID, fakeAppName)),
-							)
-						})
+								It("fails to watch multiple metrics", func() {
+									session := runPluginCommand(ts, "autoscaling-metrics", fakeAppName, metricName+",cpu", "--watch")
 
-						JustBeforeEach(func() {
-							args = []string{"autoscaling-api", autoscalerEndpoint.String()}
-							runPluginCommand(ts, args...)
-						})
+									Expect(session).To(gbytes.Say(ui.MultipleMetrics))
+									Expect(session.ExitCode()).To(Equal(1))
+								})
+							})
 
-						When("access token is wrong", func() {
-							BeforeEach(func() {
-								apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
-									ghttp.CombineHandlers(
-										ghttp.RespondWith(http.StatusUnauthorized, ""),
-									),
+							When("watching the metrics", func() {
+								var (
+									polls     int32
+									startTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+									metric    = func(minutes int, value string) *AppAggregatedMetric {
+										return &AppAggregatedMetric{
+											AppId:     fakeAppID,
+											Name:      metricName,
+											Unit:      "MB",
+											Value:     value,
+											Timestamp: startTime.Add(time.Duration(minutes) * time.Minute).UnixNano(),
+										}
+									}
 								)
-							})
 
-							It("failed with 401 error", func() {
-								args = []string{"autoscaling-metrics", fakeAppName, metricName}
-								session := runPluginCommand(ts, args...)
+								BeforeEach(func() {
+									atomic.StoreInt32(&polls, 0)
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+										ghttp.RespondWith(http.StatusOK, `{
+											"instance_min_count": 1,
+											"instance_max_count": 5,
+											"scaling_rules": [
+												{"metric_type": "memoryused", "threshold": 30, "operator": "<", "adjustment": "-1"},
+												{"metric_type": "memoryused", "threshold": 80, "operator": ">=", "adjustment": "+1"},
+												{"metric_type": "cpu", "threshold": 10, "operator": ">", "adjustment": "+1"}
+											]}`),
+									)
+									apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
+										func(w http.ResponseWriter, req *http.Request) {
+											defer GinkgoRecover()
+											poll := atomic.AddInt32(&polls, 1)
+											Expect(req.URL.Query().Get("order")).To(Equal("asc"))
+
+											if poll == 2 {
+												ghttp.RespondWith(http.StatusInternalServerError, `{"error":"temporarily unavailable"}`)(w, req)
+												return
+											}
 
-								Expect(session).To(gbytes.Say("Failed to access AutoScaler API endpoint"))
-								Expect(session.ExitCode()).To(Equal(1))
-							})
-						})
+											// the third data point arrives after the failed poll
+											points := []*AppAggregatedMetric{metric(1, "50"), metric(2, "20")}
+											if poll > 2 {
+												points = append(points, metric(3, "85"))
+											}
+											cursor, err := strconv.ParseInt(req.URL.Query().Get("start-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											var results []*AppAggregatedMetric
+											for _, point := range points {
+												if point.Timestamp >= cursor {
+													results = append(results, point)
+												}
+											}
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+												TotalResults: uint32(len(results)),
+												TotalPages:   1,
+												Page:         1,
+												Metrics:      results,
+											})(w, req)
+										},
+									)
+								})
 
-						When("access token is correct", func() {
+								It("prints new metrics once, annotated with the crossed thresholds, until interrupted", func() {
+									args = []string{"autoscaling-metrics", fakeAppName, metricName, "--watch", "--interval", "100ms", "--start", "2026-01-01T00:00:00Z"}
+									session := startPluginCommand(ts, args...)
+
+									Eventually(session.Out).Should(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.WatchAggregatedMetricHint, metricName, fakeAppName))))
+									Eventually(session.Out).Should(gbytes.Say(`Threshold Crossed`))
+									Eventually(session.Out).Should(gbytes.Say(`memoryused\s+50MB\s+\S+\s*\n`))
+									Eventually(session.Out).Should(gbytes.Say(`memoryused\s+20MB\s+\S+\s+< 30 \(-1\)`))
+									Eventually(session.Out).Should(gbytes.Say(`Failed to retrieve aggregated metrics, retrying in 100ms: temporarily unavailable`))
+									Eventually(session.Out).Should(gbytes.Say(`memoryused\s+85MB\s+\S+\s+>= 80 \(\+1\)`))
+									Eventually(func() int32 { return atomic.LoadInt32(&polls) }).Should(BeNumerically(">=", 4))
+
+									session.Interrupt()
+									Eventually(session).Should(gexec.Exit(0))
+									Expect(strings.Count(string(session.Out.Contents()), "20MB")).To(Equal(1))
+									Expect(strings.Count(string(session.Out.Contents()), "85MB")).To(Equal(1))
+								})
 
-							BeforeEach(func() {
-								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
-									*retVal = fakeAccessToken
-									return nil
-								}
+								It("prints every metric of a poll spanning several pages once", func() {
+									// the server pages the metrics newer than start-time, two per page
+									points := []*AppAggregatedMetric{metric(1, "51"), metric(2, "52"), metric(3, "53"), metric(4, "54")}
+									apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
+										func(w http.ResponseWriter, req *http.Request) {
+											defer GinkgoRecover()
+											atomic.AddInt32(&polls, 1)
+											cursor, err := strconv.ParseInt(req.URL.Query().Get("start-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											page, err := strconv.Atoi(req.URL.Query().Get("page"))
+											Expect(err).NotTo(HaveOccurred())
+											var results []*AppAggregatedMetric
+											for _, point := range points {
+												if point.Timestamp >= cursor {
+													results = append(results, point)
+												}
+											}
+											total := len(results)
+											results = results[min(2*(page-1), total):min(2*page, total)]
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{
+												TotalResults: uint32(total),
+												TotalPages:   uint16((total + 1) / 2),
+												Page:         uint16(page),
+												Metrics:      results,
+											})(w, req)
+										},
+									)
+
+									args = []string{"autoscaling-metrics", fakeAppName, metricName, "--watch", "--interval", "100ms", "--start", "2026-01-01T00:00:00Z"}
+									session := startPluginCommand(ts, args...)
+
+									Eventually(session.Out).Should(gbytes.Say(`memoryused\s+54MB`))
+									Eventually(func() int32 { return atomic.LoadInt32(&polls) }).Should(BeNumerically(">=", 4))
+
+									session.Interrupt()
+									Eventually(session).Should(gexec.Exit(0))
+									for _, value := range []string{"51MB", "52MB", "53MB", "54MB"} {
+										Expect(strings.Count(string(session.Out.Contents()), value)).To(Equal(1))
+									}
+								})
 							})
+
 							When("no aggregated metric record in desired duration", func() {
 								BeforeEach(func() {
 									apiServer.RouteToHandler("GET", aggregatedMetricsURLPath,
@@ -1590,6 +3222,49 @@ var _ = Describe("App-AutoScaler Commands", func() {
 										Expect(session.ExitCode()).To(Equal(0))
 									})
 
+									It("Succeed to print all pages of the metrics to stdout in json format", func() {
+
+										args = []string{"autoscaling-metrics", fakeAppName, metricName, "--format", "json"}
+										session := runPluginCommand(ts, args...)
+
+										var actualMetrics []*AppAggregatedMetric
+										err = json.Unmarshal(session.Out.Contents(), &actualMetrics)
+										Expect(err).NotTo(HaveOccurred())
+										Expect(actualMetrics).To(Equal(reversedMetrics[0:20]))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed to print all pages of the metrics to stdout in jsonl format", func() {
+
+										args = []string{"autoscaling-metrics", fakeAppName, metricName, "--format", "jsonl"}
+										session := runPluginCommand(ts, args...)
+
+										lines := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+										Expect(lines).To(HaveLen(20))
+										var actualMetric AppAggregatedMetric
+										err = json.Unmarshal([]byte(lines[19]), &actualMetric)
+										Expect(err).NotTo(HaveOccurred())
+										Expect(actualMetric).To(Equal(*reversedMetrics[19]))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed to print all pages of the metrics to a file in csv format", func() {
+
+										args = []string{"autoscaling-metrics", fakeAppName, metricName, "--format", "csv", "--output", outputFile}
+										session := runPluginCommand(ts, args...)
+
+										Expect(session.Out).To(gbytes.Say(ui.SaveAggregatedMetricHint, fakeAppName, outputFile))
+										Expect(session.Out).To(gbytes.Say("OK"))
+										Expect(session.ExitCode()).To(Equal(0))
+
+										contents, err := os.ReadFile(outputFile)
+										Expect(err).NotTo(HaveOccurred())
+										rows := strings.Split(strings.TrimSpace(string(contents)), "\n")
+										Expect(rows).To(HaveLen(21))
+										Expect(rows[0]).To(Equal("app_id,name,unit,value,timestamp"))
+										Expect(rows[1]).To(Equal(fmt.Sprintf("%s,memoryused,MB,100,%d", fakeAppID, reversedMetrics[0].Timestamp)))
+									})
+
 								})
 
 								Context("Query multiple pages with desc order ", func() {
@@ -1951,6 +3626,46 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					Expect(session.ExitCode()).To(Equal(1))
 				})
 
+				It("Failed when the time zone is unknown", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--timezone", "Mars/Olympus_Mons"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Unrecognized time zone: Mars/Olympus_Mons"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when the time format has no element of the reference time", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--time-format", "iso"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Unrecognized time format: iso"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --since is used with --start", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--since", "2h", "--start", "-30m"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.SinceWithStartTime))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --since is not a positive duration", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--since", "yesterday"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Invalid duration for --since: yesterday"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when a relative start time is after the end time", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--start", "-30m", "--end", "-1h"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Invalid time range. The start time -30m is greater than the end time -1h"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
 				It("Failed when start/end time is prior to 1970-01-01T00:00:00Z", func() {
 					args = []string{"autoscaling-history", fakeAppName,
 						"--start", "1969-12-31-T00:00:00Z",
@@ -2000,6 +3715,30 @@ var _ = Describe("App-AutoScaler Commands", func() {
 					Expect(session.ExitCode()).To(Equal(1))
 				})
 
+				It("Failed when --follow is used with --end", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--follow", "--end", "2018-01-01T00:00:00Z"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.FollowWithEndTime))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when --follow is used with a machine-readable format", func() {
+					args = []string{"autoscaling-history", fakeAppName, "-f", "--format", "json"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say(ui.FollowWithFormat))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
+				It("Failed when the poll interval is not positive", func() {
+					args = []string{"autoscaling-history", fakeAppName, "--follow", "--interval", "0s"}
+					session := runPluginCommand(ts, args...)
+
+					Expect(session).To(gbytes.Say("Invalid poll interval 0s"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+
 			})
 
 			When("cf api is not set ", func() {
@@ -2131,13 +3870,305 @@ var _ = Describe("App-AutoScaler Commands", func() {
 							})
 						})
 
-						When("access token is correct", func() {
+						When("access token is correct", func() {
+
+							BeforeEach(func() {
+								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+									*retVal = fakeAccessToken
+									return nil
+								}
+							})
+
+							When("filtering the history", func() {
+								var eventTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+								BeforeEach(func() {
+									event := func(minutes int, scalingType ScalingType, status ScalingStatus, oldInstances, newInstances int, reason string) *AppScalingHistory {
+										return &AppScalingHistory{
+											AppId:        fakeAppID,
+											Timestamp:    eventTime.Add(time.Duration(minutes) * time.Minute).UnixNano(),
+											ScalingType:  scalingType,
+											Status:       status,
+											OldInstances: oldInstances,
+											NewInstances: newInstances,
+											Reason:       reason,
+										}
+									}
+									pages := [][]*AppScalingHistory{
+										{
+											event(4, ScalingTypeDynamic, ScalingStatusFailed, 3, 4, "+1 instance(s) because memoryused >= 80MB for 120 seconds"),
+											event(3, ScalingTypeScheduled, ScalingStatusSucceeded, 5, 2, "schedule ends"),
+										},
+										{
+											event(2, ScalingTypeDynamic, ScalingStatusSucceeded, 2, 3, "+1 instance(s) because CPU >= 80% for 120 seconds"),
+											event(1, ScalingTypeDynamic, ScalingStatusFailed, 3, 2, "-1 instance(s) because memoryused < 30MB for 120 seconds"),
+										},
+									}
+									apiServer.RouteToHandler("GET", urlpath,
+										func(w http.ResponseWriter, req *http.Request) {
+											page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+												TotalResults: 4,
+												TotalPages:   2,
+												Page:         uint16(page),
+												Histories:    pages[page-1],
+											})(w, req)
+										},
+									)
+								})
+
+								It("prints the failed scale-outs of all pages", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--status", "failed", "--direction", "out")
+
+									Expect(session.Out).To(gbytes.Say(`dynamic\s+failed\s+2026-01-01T\S+\s+\+1 instance\(s\) because memoryused >= 80MB`))
+									Expect(session.Out).NotTo(gbytes.Say(`instance\(s\)`))
+									Expect(session.Err.Contents()).To(BeEmpty())
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("combines the type and reason filters", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--type", "dynamic", "--reason-contains", "MEMORYUSED", "--format", "csv")
+
+									rows := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+									Expect(rows).To(HaveLen(3))
+									Expect(rows[1]).To(ContainSubstring("memoryused >= 80MB"))
+									Expect(rows[2]).To(ContainSubstring("memoryused < 30MB"))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("tells when no event matches", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--type", "scheduled", "--direction", "out")
+
+									Expect(session.Out).To(gbytes.Say(fmt.Sprintf(ui.HistoryNotMatched, fakeAppName)))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("rejects an unknown status", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--status", "pending")
+
+									Expect(session).To(gbytes.Say("Invalid value `pending' for option `--status'"))
+									Expect(session.ExitCode()).To(Equal(1))
+								})
+							})
+
+							When("following the history", func() {
+								var (
+									polls     int32
+									startTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+									event     = func(minutes int, newInstances int) *AppScalingHistory {
+										return &AppScalingHistory{
+											AppId:        fakeAppID,
+											Timestamp:    startTime.Add(time.Duration(minutes) * time.Minute).UnixNano(),
+											ScalingType:  0,
+											Status:       0,
+											OldInstances: newInstances - 1,
+											NewInstances: newInstances,
+										}
+									}
+								)
+
+								BeforeEach(func() {
+									atomic.StoreInt32(&polls, 0)
+									apiServer.RouteToHandler("GET", urlpath,
+										func(w http.ResponseWriter, req *http.Request) {
+											defer GinkgoRecover()
+											poll := atomic.AddInt32(&polls, 1)
+											Expect(req.URL.Query().Get("order")).To(Equal("asc"))
+
+											if poll == 2 {
+												ghttp.RespondWith(http.StatusInternalServerError, `{"error":"temporarily unavailable"}`)(w, req)
+												return
+											}
+
+											// the third event happens after the failed poll
+											events := []*AppScalingHistory{event(1, 2), event(2, 3)}
+											if poll > 2 {
+												events = append(events, event(3, 4))
+											}
+											cursor, err := strconv.ParseInt(req.URL.Query().Get("start-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											Expect(cursor).To(BeNumerically(">=", startTime.UnixNano()-1))
+											var histories []*AppScalingHistory
+											for _, history := range events {
+												if history.Timestamp >= cursor {
+													histories = append(histories, history)
+												}
+											}
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+												TotalResults: uint32(len(histories)),
+												TotalPages:   1,
+												Page:         1,
+												Histories:    histories,
+											})(w, req)
+										},
+									)
+								})
+
+								It("prints new events only once and keeps polling through errors until interrupted", func() {
+									args = []string{"autoscaling-history", fakeAppName, "--follow", "--interval", "100ms", "--start", "2026-01-01T00:00:00Z"}
+									session := startPluginCommand(ts, args...)
+
+									Eventually(session.Out).Should(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.FollowHistoryHint, fakeAppName))))
+									Eventually(session.Out).Should(gbytes.Say(`dynamic\s+succeeded\s+1->2`))
+									Eventually(session.Out).Should(gbytes.Say(`dynamic\s+succeeded\s+2->3`))
+									Eventually(session.Out).Should(gbytes.Say(`Failed to retrieve scaling event history, retrying in 100ms: temporarily unavailable`))
+									Eventually(session.Out).Should(gbytes.Say(`dynamic\s+succeeded\s+3->4`))
+									Eventually(func() int32 { return atomic.LoadInt32(&polls) }).Should(BeNumerically(">=", 4))
+
+									session.Interrupt()
+									Eventually(session).Should(gexec.Exit(0))
+									Expect(strings.Count(string(session.Out.Contents()), "2->3")).To(Equal(1))
+									Expect(strings.Count(string(session.Out.Contents()), "3->4")).To(Equal(1))
+								})
+
+								It("prints every event of a poll spanning several pages once", func() {
+									// the server pages the events newer than start-time, two per page
+									events := []*AppScalingHistory{event(1, 2), event(2, 3), event(3, 4), event(4, 5)}
+									apiServer.RouteToHandler("GET", urlpath,
+										func(w http.ResponseWriter, req *http.Request) {
+											defer GinkgoRecover()
+											atomic.AddInt32(&polls, 1)
+											cursor, err := strconv.ParseInt(req.URL.Query().Get("start-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											page, err := strconv.Atoi(req.URL.Query().Get("page"))
+											Expect(err).NotTo(HaveOccurred())
+											var histories []*AppScalingHistory
+											for _, history := range events {
+												if history.Timestamp >= cursor {
+													histories = append(histories, history)
+												}
+											}
+											total := len(histories)
+											histories = histories[min(2*(page-1), total):min(2*page, total)]
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+												TotalResults: uint32(total),
+												TotalPages:   uint16((total + 1) / 2),
+												Page:         uint16(page),
+												Histories:    histories,
+											})(w, req)
+										},
+									)
+
+									args = []string{"autoscaling-history", fakeAppName, "--follow", "--interval", "100ms", "--start", "2026-01-01T00:00:00Z"}
+									session := startPluginCommand(ts, args...)
+
+									Eventually(session.Out).Should(gbytes.Say(`dynamic\s+succeeded\s+4->5`))
+									Eventually(func() int32 { return atomic.LoadInt32(&polls) }).Should(BeNumerically(">=", 4))
+
+									session.Interrupt()
+									Eventually(session).Should(gexec.Exit(0))
+									for _, change := range []string{"1->2", "2->3", "3->4", "4->5"} {
+										Expect(strings.Count(string(session.Out.Contents()), change)).To(Equal(1))
+									}
+								})
+							})
+
+							When("timestamps are displayed in another time zone", func() {
+								var eventTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+								BeforeEach(func() {
+									apiServer.RouteToHandler("GET", urlpath,
+										ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+											TotalResults: 1,
+											TotalPages:   1,
+											Page:         1,
+											Histories: []*AppScalingHistory{{
+												AppId:        fakeAppID,
+												Timestamp:    eventTime.UnixNano(),
+												OldInstances: 1,
+												NewInstances: 2,
+											}},
+										}),
+									)
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+										ghttp.RespondWith(http.StatusOK, `{
+											"instance_min_count": 1,
+											"instance_max_count": 5,
+											"schedules": {
+												"timezone": "Asia/Tokyo",
+												"specific_date": [{"start_date_time": "2099-01-01T10:00", "end_date_time": "2099-01-01T12:00", "instance_min_count": 2, "instance_max_count": 5}]
+											}}`),
+									)
+								})
+
+								It("formats the table in the given time zone and format", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--timezone", "UTC", "--time-format", "datetime")
+
+									Expect(session.Out).To(gbytes.Say(`dynamic\s+succeeded\s+1->2\s+2026-01-01 00:00:00`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("formats the table in the time zone of the schedules", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--timezone", "policy")
+
+									Expect(session.Out).To(gbytes.Say(`1->2\s+2026-01-01T09:00:00\+09:00`))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("adds the formatted time to the exports", func() {
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--format", "csv", "--timezone", "America/New_York")
+
+									rows := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+									Expect(rows).To(HaveLen(2))
+									Expect(rows[0]).To(Equal("app_id,timestamp,scaling_type,status,old_instances,new_instances,reason,message,error,time"))
+									Expect(rows[1]).To(HaveSuffix(",2025-12-31T19:00:00-05:00"))
+									Expect(session.ExitCode()).To(Equal(0))
+
+									session = runPluginCommand(ts, "autoscaling-history", fakeAppName, "--format", "json", "--time-format", "unix")
+									Expect(session.Out.Contents()).To(MatchJSON(fmt.Sprintf(`[{"app_id":"%s","timestamp":%d,"scaling_type":0,"status":0,"old_instances":1,"new_instances":2,"reason":"","message":"","error":"","time":"1767225600"}]`,
+										fakeAppID, eventTime.UnixNano())))
+									Expect(session.ExitCode()).To(Equal(0))
+								})
+
+								It("fails with the policy time zone if the app has no schedules", func() {
+									apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+										ghttp.RespondWith(http.StatusOK, `{"instance_min_count": 1, "instance_max_count": 5, "scaling_rules": [{"metric_type": "cpu", "threshold": 10, "operator": ">", "adjustment": "+1"}]}`),
+									)
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--timezone", "policy")
+
+									Expect(session.Out).To(gbytes.Say(fmt.Sprintf(ui.NoPolicyTimezone, fakeAppName)))
+									Expect(session.ExitCode()).To(Equal(1))
+								})
+							})
+
+							When("relative times are given", func() {
+								var startTime, endTime int64
+
+								BeforeEach(func() {
+									apiServer.RouteToHandler("GET", urlpath,
+										func(w http.ResponseWriter, req *http.Request) {
+											defer GinkgoRecover()
+											var err error
+											startTime, err = strconv.ParseInt(req.URL.Query().Get("start-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											endTime, err = strconv.ParseInt(req.URL.Query().Get("end-time"), 10, 64)
+											Expect(err).NotTo(HaveOccurred())
+											ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{Page: 1, Histories: []*AppScalingHistory{}})(w, req)
+										},
+									)
+								})
+
+								It("queries the duration given by --since up to now", func() {
+									before := time.Now()
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--since", "2h")
+									Expect(session.ExitCode()).To(Equal(0))
+
+									Expect(startTime).To(BeNumerically("~", before.Add(-2*time.Hour).UnixNano(), int64(time.Minute)))
+									Expect(endTime).To(BeNumerically("~", before.UnixNano(), int64(time.Minute)))
+								})
 
-							BeforeEach(func() {
-								rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
-									*retVal = fakeAccessToken
-									return nil
-								}
+								It("accepts durations before now, dates and epoch milliseconds", func() {
+									before := time.Now()
+									session := runPluginCommand(ts, "autoscaling-history", fakeAppName, "--start", "-1d", "--end", "now")
+									Expect(session.ExitCode()).To(Equal(0))
+									Expect(startTime).To(BeNumerically("~", before.Add(-24*time.Hour).UnixNano(), int64(time.Minute)))
+									Expect(endTime).To(BeNumerically("~", before.UnixNano(), int64(time.Minute)))
+
+									session = runPluginCommand(ts, "autoscaling-history", fakeAppName, "--start", "2026-01-01", "--end", "1767312000000")
+									Expect(session.ExitCode()).To(Equal(0))
+									Expect(startTime).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local).UnixNano()))
+									Expect(endTime).To(Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano()))
+								})
 							})
 
 							When("no history record in desired duration", func() {
@@ -2279,6 +4310,30 @@ var _ = Describe("App-AutoScaler Commands", func() {
 										Expect(session.ExitCode()).To(Equal(0))
 									})
 
+									It("Succeed to print all pages of the histories to stdout in json format", func() {
+
+										args = []string{"autoscaling-history", fakeAppName, "--format", "json"}
+										session := runPluginCommand(ts, args...)
+
+										var actualHistories []*AppScalingHistory
+										err = json.Unmarshal(session.Out.Contents(), &actualHistories)
+										Expect(err).NotTo(HaveOccurred())
+										Expect(actualHistories).To(Equal(reversedHistories[0:20]))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
+									It("Succeed to print all pages of the histories to stdout in csv format", func() {
+
+										args = []string{"autoscaling-history", fakeAppName, "--format", "csv"}
+										session := runPluginCommand(ts, args...)
+
+										rows := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+										Expect(rows).To(HaveLen(21))
+										Expect(rows[0]).To(Equal("app_id,timestamp,scaling_type,status,old_instances,new_instances,reason,message,error"))
+										Expect(rows[1]).To(Equal(fmt.Sprintf("%s,%d,1,1,30,31,fakeReason,,fakeError", fakeAppID, reversedHistories[0].Timestamp)))
+										Expect(session.ExitCode()).To(Equal(0))
+									})
+
 								})
 
 								Context("Query multiple pages with desc order ", func() {
@@ -2426,6 +4481,42 @@ var _ = Describe("App-AutoScaler Commands", func() {
 											}
 											Expect(session.ExitCode()).To(Equal(0))
 										})
+
+										It("Succeed to export the history with specified desc, warning on stderr", func() {
+
+											args = []string{"autoscaling-history", fakeAppName,
+												"--start", now.Format(time.RFC3339),
+												"--end", time.Unix(0, lowPrecisionNowInNano+int64(29*120*1e9)).Format(time.RFC3339),
+												"--desc", "--format", "json",
+											}
+
+											session := runPluginCommand(ts, args...)
+
+											var actualHistories []*AppScalingHistory
+											Expect(json.Unmarshal(session.Out.Contents(), &actualHistories)).To(Succeed())
+											Expect(actualHistories).To(HaveLen(30))
+											Expect(session.Err).To(gbytes.Say(ui.DeprecatedDescWarning))
+											Expect(session.ExitCode()).To(Equal(0))
+										})
+
+										It("Succeed to overwrite a longer output file", func() {
+											Expect(os.WriteFile(outputFile, bytes.Repeat([]byte("x"), 64*1024), 0666)).To(Succeed())
+
+											args = []string{"autoscaling-history", fakeAppName,
+												"--start", now.Format(time.RFC3339),
+												"--end", time.Unix(0, lowPrecisionNowInNano+int64(29*120*1e9)).Format(time.RFC3339),
+												"--desc", "--format", "json", "--output", outputFile,
+											}
+
+											session := runPluginCommand(ts, args...)
+											Expect(session.ExitCode()).To(Equal(0))
+
+											contents, err := os.ReadFile(outputFile)
+											Expect(err).NotTo(HaveOccurred())
+											var actualHistories []*AppScalingHistory
+											Expect(json.Unmarshal(contents, &actualHistories)).To(Succeed())
+											Expect(actualHistories).To(HaveLen(30))
+										})
 									})
 
 									Context("specify --end only ", func() {
@@ -2686,6 +4777,625 @@ var _ = Describe("App-AutoScaler Commands", func() {
 		})
 	})
 
+	Describe("Commands autoscaling-apps, asapps", func() {
+
+		const otherAppID, otherAppName = "otherAppId", "otherAppName"
+
+		Context("autoscaling-apps", func() {
+
+			When("cf not login", func() {
+				It("exits with 'You must be logged in' error ", func() {
+					args = []string{"autoscaling-apps"}
+					session := runPluginCommand(ts, args...)
+					Expect(session).To(gbytes.Say("You must be logged in"))
+					Expect(session.ExitCode()).To(Equal(1))
+				})
+			})
+
+			When("logged in and targeting a space", func() {
+				BeforeEach(func() {
+					setLoggedIn(rpcHandlers)
+					setTargeted(rpcHandlers)
+					rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+						*retVal = fakeAccessToken
+						return nil
+					}
+				})
+
+				JustBeforeEach(func() {
+					args = []string{"autoscaling-api", autoscalerEndpoint.String()}
+					runPluginCommand(ts, args...)
+				})
+
+				When("there are no apps in the space", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("GET", "/v3/apps",
+							ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+						)
+					})
+
+					It("Succeed with no apps found", func() {
+						args = []string{"autoscaling-apps"}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(ui.ShowAppsHint, "fakeSpace"))
+						Expect(session.Out).To(gbytes.Say("OK"))
+						Expect(session.Out).To(gbytes.Say(ui.AppsNotFound, "fakeSpace"))
+						Expect(session.ExitCode()).To(Equal(0))
+					})
+				})
+
+				When("there are apps with and without policy", func() {
+					BeforeEach(func() {
+						apiServer.RouteToHandler("GET", "/v3/apps",
+							ghttp.CombineHandlers(
+								ghttp.VerifyFormKV("space_guids", "fakeSpaceGuid"),
+								ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[
+									{"guid": "%s", "name": "%s"},
+									{"guid": "%s", "name": "%s"}]}`, otherAppID, otherAppName, fakeAppID, fakeAppName)),
+							),
+						)
+						apiServer.RouteToHandler("GET", "/v3/processes",
+							ghttp.CombineHandlers(
+								ghttp.VerifyFormKV("types", "web"),
+								ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[
+									{"guid": "process1", "type": "web", "instances": 2, "relationships": {"app": {"data": {"guid": "%s"}}}},
+									{"guid": "process2", "type": "web", "instances": 1, "relationships": {"app": {"data": {"guid": "%s"}}}}]}`, fakeAppID, otherAppID)),
+							),
+						)
+						apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+							ghttp.CombineHandlers(
+								ghttp.VerifyHeaderKV("Authorization", fakeAccessToken),
+								ghttp.RespondWithJSONEncoded(http.StatusOK, &fakePolicy),
+							),
+						)
+						apiServer.RouteToHandler("GET", "/v1/apps/"+otherAppID+"/policy",
+							ghttp.RespondWith(http.StatusNotFound, ""),
+						)
+						apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/scaling_histories",
+							ghttp.CombineHandlers(
+								ghttp.VerifyFormKV("order", "desc"),
+								ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+									TotalResults: 1,
+									TotalPages:   1,
+									Page:         1,
+									Histories: []*AppScalingHistory{
+										{
+											AppId:        fakeAppID,
+											Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano(),
+											ScalingType:  0,
+											Status:       0,
+											OldInstances: 1,
+											NewInstances: 2,
+										},
+									},
+								}),
+							),
+						)
+					})
+
+					It("Succeed to list the apps sorted by name", func() {
+						args = []string{"autoscaling-apps"}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).To(gbytes.Say(ui.ShowAppsHint, "fakeSpace"))
+						Expect(session.Out).To(gbytes.Say("OK"))
+						Expect(session.Out).To(gbytes.Say(`App Name\s+Instances\s+Min\s+Max\s+Rules\s+Schedules\s+Last Scaling Event`))
+						Expect(session.Out).To(gbytes.Say(`fakeAppName\s+2\s+1\s+2\s+2\s+2\s+` + time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Local().Format(time.RFC3339) + ` dynamic 1->2`))
+						Expect(session.Out).To(gbytes.Say(`otherAppName\s+1\s+-\s+-\s+-\s+-\s+-`))
+						Expect(session.ExitCode()).To(Equal(0))
+					})
+
+					It("shows the error of an app whose policy cannot be retrieved and lists the others", func() {
+						var healthChecks int32
+						apiServer.RouteToHandler("GET", "/health",
+							func(w http.ResponseWriter, req *http.Request) {
+								atomic.AddInt32(&healthChecks, 1)
+								ghttp.RespondWith(http.StatusOK, "")(w, req)
+							},
+						)
+						apiServer.RouteToHandler("GET", "/v1/apps/"+otherAppID+"/policy",
+							ghttp.RespondWith(http.StatusUnauthorized, ""),
+						)
+
+						args = []string{"autoscaling-apps"}
+						session := runPluginCommand(ts, args...)
+
+						Expect(session.Out).NotTo(gbytes.Say("OK"))
+						Expect(session.Out).To(gbytes.Say(`fakeAppName\s+2\s+1\s+2\s+2\s+2\s+\S+ dynamic 1->2`))
+						Expect(session.Out).To(gbytes.Say(`otherAppName\s+1\s+-\s+-\s+-\s+-\s+Unauthorized. Failed to access AutoScaler API endpoint`))
+						Expect(session.Out).To(gbytes.Say(fmt.Sprintf(ui.AppsOverviewFailed, 1)))
+						Expect(session.ExitCode()).To(Equal(1))
+						Expect(atomic.LoadInt32(&healthChecks)).To(Equal(int32(1)))
+					})
+				})
+			})
+		})
+	})
+
+	Describe("Commands autoscaling-report, asr", func() {
+
+		var (
+			historyURLPath = "/v1/apps/" + fakeAppID + "/scaling_histories"
+			reportStart    = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			at             = func(minutes int) int64 {
+				return reportStart.Add(time.Duration(minutes) * time.Minute).UnixNano()
+			}
+			event = func(minutes int, scalingType ScalingType, status ScalingStatus, oldInstances, newInstances int) *AppScalingHistory {
+				return &AppScalingHistory{
+					AppId:        fakeAppID,
+					Timestamp:    at(minutes),
+					ScalingType:  scalingType,
+					Status:       status,
+					OldInstances: oldInstances,
+					NewInstances: newInstances,
+				}
+			}
+		)
+
+		It("Require APP_NAME as argument", func() {
+			session := runPluginCommand(ts, "autoscaling-report")
+
+			Expect(session).To(gbytes.Say("the required argument `APP_NAME` was not provided"))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		When("logged in and targeting a space", func() {
+			BeforeEach(func() {
+				setLoggedIn(rpcHandlers)
+				setTargeted(rpcHandlers)
+				rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+					*retVal = fakeAccessToken
+					return nil
+				}
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName)),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+					ghttp.RespondWith(http.StatusOK, `{
+						"instance_min_count": 1,
+						"instance_max_count": 4,
+						"scaling_rules": [
+							{"metric_type": "memoryused", "threshold": 300, "operator": ">=", "adjustment": "+2", "cool_down_secs": 300},
+							{"metric_type": "cpu", "threshold": 10, "operator": "<", "adjustment": "-1", "cool_down_secs": 600}
+						]}`),
+				)
+				apiServer.RouteToHandler("GET", historyURLPath,
+					func(w http.ResponseWriter, req *http.Request) {
+						endTime, _ := strconv.ParseInt(req.URL.Query().Get("end-time"), 10, 64)
+						histories := []*AppScalingHistory{
+							event(60, ScalingTypeDynamic, ScalingStatusSucceeded, 2, 4),
+							event(65, ScalingTypeDynamic, ScalingStatusSucceeded, 4, 3),
+							event(180, ScalingTypeDynamic, ScalingStatusFailed, 3, 4),
+							event(300, ScalingTypeScheduled, ScalingStatusSucceeded, 3, 1),
+						}
+						// the last scaling before the report sets the initial instances
+						if endTime < reportStart.UnixNano() {
+							histories = []*AppScalingHistory{event(-60, ScalingTypeDynamic, ScalingStatusSucceeded, 1, 2)}
+						}
+						ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+							TotalResults: uint32(len(histories)),
+							TotalPages:   1,
+							Page:         1,
+							Histories:    histories,
+						})(w, req)
+					},
+				)
+				var metrics []*AppAggregatedMetric
+				for i, value := range []string{"100", "200", "300"} {
+					metrics = append(metrics, &AppAggregatedMetric{AppId: fakeAppID, Name: "memoryused", Unit: "MB", Value: value, Timestamp: at(i * 60)})
+				}
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/aggregated_metric_histories/memoryused",
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{TotalResults: 3, TotalPages: 1, Page: 1, Metrics: metrics}),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/aggregated_metric_histories/cpu",
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{TotalResults: 0, TotalPages: 1, Page: 1}),
+				)
+			})
+
+			JustBeforeEach(func() {
+				runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+			})
+
+			It("prints the report", func() {
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T10:00:00Z")
+
+				Expect(session.Out).To(gbytes.Say(`Scale-out events\s+1`))
+				Expect(session.Out).To(gbytes.Say(`Scale-in events\s+2`))
+				Expect(session.Out).To(gbytes.Say(`Failed events\s+1 \(failure rate 25%\)`))
+				Expect(session.Out).To(gbytes.Say(`Instance-hours\s+19.08`))
+				Expect(session.Out).To(gbytes.Say(`Peak instances\s+4`))
+				Expect(session.Out).To(gbytes.Say(`Average instances\s+1.91`))
+				Expect(session.Out).To(gbytes.Say(`Flapping pairs\s+1 \(cool-down 600s\)`))
+				Expect(session.Out).To(gbytes.Say(`Time at min instances \(1\)\s+5h0m0s \(50%\)`))
+				Expect(session.Out).To(gbytes.Say(`Time at max instances \(4\)\s+5m0s \(0.83%\)`))
+				Expect(session.Out).To(gbytes.Say(`Metrics Name\s+Count\s+Mean\s+P95\s+Max`))
+				Expect(session.Out).To(gbytes.Say(`memoryused\s+3\s+200MB\s+290MB\s+300MB`))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("prints the time range in the given time zone and format", func() {
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T10:00:00Z",
+					"--timezone", "Asia/Tokyo", "--time-format", "datetime")
+
+				Expect(session.Out).To(gbytes.Say(`Time range\s+2026-01-01 09:00:00 - 2026-01-01 19:00:00`))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("fails with an invalid time format", func() {
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName, "--time-format", "long")
+
+				Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.InvalidTimeFormat, "long"))))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("exports the report as JSON", func() {
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T10:00:00Z", "--format", "json")
+
+				var report map[string]interface{}
+				Expect(json.Unmarshal(session.Out.Contents(), &report)).To(Succeed())
+				Expect(report).To(HaveKeyWithValue("app_name", fakeAppName))
+				Expect(report).To(HaveKeyWithValue("scale_out_events", BeNumerically("==", 1)))
+				Expect(report).To(HaveKeyWithValue("failure_rate", BeNumerically("==", 0.25)))
+				Expect(report).To(HaveKeyWithValue("instance_hours", BeNumerically("==", 19.08)))
+				Expect(report).To(HaveKeyWithValue("secs_at_instance_min_count", BeNumerically("==", 18000)))
+				Expect(report["metrics"]).To(HaveLen(1))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("fails without a policy", func() {
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				)
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName)
+
+				Expect(session).To(gbytes.Say(ui.PolicyNotFound, fakeAppName))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+
+			It("takes the current instances of the app by GUID without scaling events", func() {
+				setUntargeted(rpcHandlers)
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusInternalServerError, ""),
+				)
+				apiServer.RouteToHandler("GET", "/v3/processes",
+					ghttp.CombineHandlers(
+						ghttp.VerifyFormKV("app_guids", fakeAppID),
+						ghttp.VerifyFormKV("types", "web"),
+						ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "web-guid", "type": "web", "instances": 3, "relationships": {"app": {"data": {"guid": "%s"}}}}]}`, fakeAppID)),
+					),
+				)
+				apiServer.RouteToHandler("GET", historyURLPath,
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{TotalResults: 0, TotalPages: 1, Page: 1}),
+				)
+				session := runPluginCommand(ts, "autoscaling-report", fakeAppName, "--app-guid", fakeAppID, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T10:00:00Z")
+
+				Expect(session.Out).To(gbytes.Say(`Peak instances\s+3`))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Commands autoscaling-simulate, assim", func() {
+
+		var (
+			simulationStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			at              = func(minutes int) int64 {
+				return simulationStart.Add(time.Duration(minutes) * time.Minute).UnixNano()
+			}
+			candidatePolicy = `{
+				"instance_min_count": 1,
+				"instance_max_count": 5,
+				"scaling_rules": [
+					{"metric_type": "memoryused", "threshold": 100, "operator": ">=", "adjustment": "+1", "breach_duration_secs": 120, "cool_down_secs": 300}
+				]}`
+		)
+
+		It("Require APP_NAME and PATH_TO_POLICY_FILE as arguments", func() {
+			session := runPluginCommand(ts, "autoscaling-simulate")
+
+			Expect(session).To(gbytes.Say("the required arguments `APP_NAME` and `PATH_TO_POLICY_FILE` were not provided"))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		When("logged in and targeting a space", func() {
+			BeforeEach(func() {
+				setLoggedIn(rpcHandlers)
+				setTargeted(rpcHandlers)
+				rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+					*retVal = fakeAccessToken
+					return nil
+				}
+				Expect(os.WriteFile(outputFile, []byte(candidatePolicy), 0666)).To(Succeed())
+
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName)),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/scaling_histories",
+					func(w http.ResponseWriter, req *http.Request) {
+						endTime, _ := strconv.ParseInt(req.URL.Query().Get("end-time"), 10, 64)
+						history := &AppScalingHistory{AppId: fakeAppID, Timestamp: at(30), OldInstances: 2, NewInstances: 3,
+							Reason: "+1 instance(s) because memoryused >= 200MB for 300 seconds"}
+						// the last scaling before the simulation sets the initial instances
+						if endTime < simulationStart.UnixNano() {
+							history = &AppScalingHistory{AppId: fakeAppID, Timestamp: at(-60), OldInstances: 1, NewInstances: 2}
+						}
+						ghttp.RespondWithJSONEncoded(http.StatusOK, &HistoryResults{
+							TotalResults: 1,
+							TotalPages:   1,
+							Page:         1,
+							Histories:    []*AppScalingHistory{history},
+						})(w, req)
+					},
+				)
+				var metrics []*AppAggregatedMetric
+				for i := 0; i <= 10; i++ {
+					metrics = append(metrics, &AppAggregatedMetric{AppId: fakeAppID, Name: "memoryused", Unit: "MB", Value: "150", Timestamp: at(i)})
+				}
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/aggregated_metric_histories/memoryused",
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &AggregatedMetricsResults{TotalResults: 11, TotalPages: 1, Page: 1, Metrics: metrics}),
+				)
+			})
+
+			JustBeforeEach(func() {
+				runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+			})
+
+			It("prints the simulated scaling next to the actual scaling", func() {
+				session := runPluginCommand(ts, "autoscaling-simulate", fakeAppName, outputFile, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T01:00:00Z")
+
+				Expect(session.Out).To(gbytes.Say(`Time\s+Source\s+Scaling Type\s+Instance Changes\s+Action`))
+				Expect(session.Out).To(gbytes.Say(`\S+\s+simulated\s+dynamic\s+2->3\s+\+1 instance\(s\) because memoryused >= 100MB for 120 seconds`))
+				Expect(session.Out).To(gbytes.Say(`\S+\s+simulated\s+dynamic\s+3->4`))
+				Expect(session.Out).To(gbytes.Say(`\S+\s+actual\s+dynamic\s+2->3\s+\+1 instance\(s\) because memoryused >= 200MB`))
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.SimulationSummary, 2, 0, fakeAppName, 1, 0))))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("prints the timestamps in the given time zone and format", func() {
+				session := runPluginCommand(ts, "autoscaling-simulate", fakeAppName, outputFile, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T01:00:00Z",
+					"--timezone", "Asia/Tokyo", "--time-format", "15:04 MST")
+
+				Expect(session.Out).To(gbytes.Say(`09:02 JST\s+simulated\s+dynamic\s+2->3`))
+				Expect(session.Out).To(gbytes.Say(`09:07 JST\s+simulated\s+dynamic\s+3->4`))
+				Expect(session.Out).To(gbytes.Say(`09:30 JST\s+actual\s+dynamic\s+2->3`))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("exports the simulation as JSON", func() {
+				session := runPluginCommand(ts, "autoscaling-simulate", fakeAppName, outputFile, "--start", "2026-01-01T00:00:00Z", "--end", "2026-01-01T01:00:00Z", "--format", "json")
+
+				var simulation struct {
+					Simulated []*SimulatedScaling  `json:"simulated"`
+					Actual    []*AppScalingHistory `json:"actual"`
+				}
+				Expect(json.Unmarshal(session.Out.Contents(), &simulation)).To(Succeed())
+				Expect(simulation.Simulated).To(HaveLen(2))
+				Expect(simulation.Simulated[0].Timestamp).To(Equal(at(2)))
+				Expect(simulation.Simulated[1].Timestamp).To(Equal(at(7)))
+				Expect(simulation.Actual).To(HaveLen(1))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("fails with an invalid policy file", func() {
+				Expect(os.WriteFile(outputFile, []byte(`{"instance_min_count": 1}`), 0666)).To(Succeed())
+				session := runPluginCommand(ts, "autoscaling-simulate", fakeAppName, outputFile)
+
+				Expect(session).To(gbytes.Say("Invalid policy"))
+				Expect(session.ExitCode()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Commands autoscaling-schedule, assch", func() {
+
+		var scheduledPolicy = `{
+			"instance_min_count": 1,
+			"instance_max_count": 5,
+			"scaling_rules": [
+				{"metric_type": "memoryused", "threshold": 100, "operator": ">=", "adjustment": "+1"}
+			],
+			"schedules": {
+				"timezone": "UTC",
+				"recurring_schedule": [
+					{"start_date": "2099-01-01", "end_date": "2099-01-02", "start_time": "08:00", "end_time": "18:00", "days_of_week": [1, 2, 3, 4, 5, 6, 7], "instance_min_count": 2, "instance_max_count": 6}
+				],
+				"specific_date": [
+					{"start_date_time": "2099-01-01T18:30", "end_date_time": "2099-01-02T09:00", "instance_min_count": 3, "instance_max_count": 8, "initial_min_instance_count": 4}
+				]
+			}}`
+
+		It("Require APP_NAME as argument", func() {
+			session := runPluginCommand(ts, "autoscaling-schedule")
+
+			Expect(session).To(gbytes.Say("the required argument `APP_NAME` was not provided"))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		It("reads the schedules of the policy file given with APP_NAME", func() {
+			Expect(os.WriteFile(outputFile, []byte(scheduledPolicy), 0666)).To(Succeed())
+			session := runPluginCommand(ts, "autoscaling-schedule", fakeAppName, "--from", outputFile, "--next", "1")
+
+			Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ShowScheduleFileHint, outputFile))))
+			Expect(session.Out).To(gbytes.Say(`recurring_schedule\[0\]\s+2099-01-01 08:00 UTC`))
+			Expect(session.ExitCode()).To(Equal(0))
+		})
+
+		It("fails with an invalid number of windows", func() {
+			session := runPluginCommand(ts, "autoscaling-schedule", fakeAppName, "--next", "0")
+
+			Expect(session).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.InvalidScheduleCount, 0))))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		It("expands the schedules of a policy file", func() {
+			Expect(os.WriteFile(outputFile, []byte(scheduledPolicy), 0666)).To(Succeed())
+			session := runPluginCommand(ts, "autoscaling-schedule", "--from", outputFile, "--next", "2")
+
+			Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ShowScheduleFileHint, outputFile))))
+			Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ScheduleTimezone, "UTC", 1, 5))))
+			Expect(session.Out).To(gbytes.Say(`Schedule\s+Start \(Policy\)\s+End \(Policy\)\s+Start \(Local\)\s+End \(Local\)\s+Min\s+Max\s+Initial\s+Notes`))
+			Expect(session.Out).To(gbytes.Say(`recurring_schedule\[0\]\s+2099-01-01 08:00 UTC\s+2099-01-01 18:00 UTC\s+\S+ \S+ \S+\s+\S+ \S+ \S+\s+2\s+6\s+2\s+short gap of 30m0s to the next window`))
+			Expect(session.Out).To(gbytes.Say(`specific_date\[0\]\s+2099-01-01 18:30 UTC\s+2099-01-02 09:00 UTC\s+\S+ \S+ \S+\s+\S+ \S+ \S+\s+3\s+8\s+4\s+overlaps recurring_schedule\[0\][ \t]*\n`))
+			Expect(session.Out).NotTo(gbytes.Say(`2099-01-02 08:00 UTC`))
+			Expect(session.ExitCode()).To(Equal(0))
+		})
+
+		When("logged in and targeting a space", func() {
+			BeforeEach(func() {
+				setLoggedIn(rpcHandlers)
+				setTargeted(rpcHandlers)
+				rpcHandlers.AccessTokenStub = func(_ string, retVal *string) error {
+					*retVal = fakeAccessToken
+					return nil
+				}
+
+				apiServer.RouteToHandler("GET", "/v3/apps",
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName)),
+				)
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+					ghttp.RespondWith(http.StatusOK, scheduledPolicy),
+				)
+			})
+
+			JustBeforeEach(func() {
+				runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+			})
+
+			It("expands the schedules of the attached policy", func() {
+				session := runPluginCommand(ts, "autoscaling-schedule", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(ui.ShowScheduleHint, fakeAppName))))
+				Expect(session.Out).To(gbytes.Say(`recurring_schedule\[0\]\s+2099-01-01 08:00 UTC`))
+				Expect(session.Out).To(gbytes.Say(`specific_date\[0\]\s+2099-01-01 18:30 UTC`))
+				Expect(session.Out).To(gbytes.Say(`recurring_schedule\[0\]\s+2099-01-02 08:00 UTC\s+2099-01-02 18:00 UTC.*overlaps specific_date\[0\]`))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+
+			It("reports a policy without schedules", func() {
+				apiServer.RouteToHandler("GET", "/v1/apps/"+fakeAppID+"/policy",
+					ghttp.RespondWith(http.StatusOK, `{"instance_min_count": 1, "instance_max_count": 5}`),
+				)
+				session := runPluginCommand(ts, "autoscaling-schedule", fakeAppName)
+
+				Expect(session.Out).To(gbytes.Say(ui.OK))
+				Expect(session.Out).To(gbytes.Say(ui.NoUpcomingSchedules))
+				Expect(session.ExitCode()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Target options --org, --space and --app-guid", func() {
+
+		var urlpath = "/v1/apps/" + fakeAppID + "/policy"
+
+		BeforeEach(func() {
+			setLoggedIn(rpcHandlers)
+			setTargeted(rpcHandlers)
+			rpcHandlers.HasOrganizationStub = func(_ string, retVal *bool) error {
+				*retVal = true
+				return nil
+			}
+			rpcHandlers.GetCurrentOrgStub = func(_ string, retVal *plugin_models.Organization) error {
+				*retVal = plugin_models.Organization{
+					OrganizationFields: plugin_models.OrganizationFields{
+						Guid: "fakeOrgGuid",
+						Name: "fakeOrg",
+					},
+				}
+				return nil
+			}
+
+			apiServer.RouteToHandler("GET", "/v3/organizations",
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("names", "otherOrg"),
+					ghttp.RespondWith(http.StatusOK, `{"resources":[{"guid": "otherOrgGuid", "name": "otherOrg"}]}`),
+				),
+			)
+			apiServer.RouteToHandler("GET", "/v3/apps",
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("space_guids", "otherSpaceGuid"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"resources":[{"guid": "%s", "name": "%s"}]}`, fakeAppID, fakeAppName)),
+				),
+			)
+			apiServer.RouteToHandler("GET", urlpath,
+				ghttp.RespondWith(http.StatusOK, `{"instance_min_count": 1, "instance_max_count": 2}`),
+			)
+		})
+
+		JustBeforeEach(func() {
+			runPluginCommand(ts, "autoscaling-api", autoscalerEndpoint.String())
+		})
+
+		It("looks up the app in a space of the targeted org", func() {
+			apiServer.RouteToHandler("GET", "/v3/spaces",
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("names", "otherSpace"),
+					ghttp.VerifyFormKV("organization_guids", "fakeOrgGuid"),
+					ghttp.RespondWith(http.StatusOK, `{"resources":[{"guid": "otherSpaceGuid", "name": "otherSpace"}]}`),
+				),
+			)
+			session := runPluginCommand(ts, "autoscaling-policy", fakeAppName, "--space", "otherSpace")
+
+			Expect(session.Out).To(gbytes.Say(ui.ShowPolicyHint, fakeAppName))
+			Expect(session.Out).To(gbytes.Say(`"instance_min_count": 1`))
+			Expect(session.ExitCode()).To(Equal(0))
+		})
+
+		It("looks up the app in a space of another org", func() {
+			apiServer.RouteToHandler("GET", "/v3/spaces",
+				ghttp.CombineHandlers(
+					ghttp.VerifyFormKV("names", "otherSpace"),
+					ghttp.VerifyFormKV("organization_guids", "otherOrgGuid"),
+					ghttp.RespondWith(http.StatusOK, `{"resources":[{"guid": "otherSpaceGuid", "name": "otherSpace"}]}`),
+				),
+			)
+			session := runPluginCommand(ts, "autoscaling-policy", fakeAppName, "--org", "otherOrg", "--space", "otherSpace")
+
+			Expect(session.Out).To(gbytes.Say(`"instance_min_count": 1`))
+			Expect(session.ExitCode()).To(Equal(0))
+		})
+
+		It("fails for a space not found", func() {
+			apiServer.RouteToHandler("GET", "/v3/spaces",
+				ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+			)
+			session := runPluginCommand(ts, "autoscaling-policy", fakeAppName, "--org", "otherOrg", "--space", "otherSpace")
+
+			Expect(session).To(gbytes.Say(fmt.Sprintf(ui.NoSpace, "otherSpace", "otherOrg")))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		It("fails for an org not found", func() {
+			apiServer.RouteToHandler("GET", "/v3/organizations",
+				ghttp.RespondWith(http.StatusOK, `{"resources":[]}`),
+			)
+			session := runPluginCommand(ts, "autoscaling-policy", fakeAppName, "--org", "missingOrg", "--space", "otherSpace")
+
+			Expect(session).To(gbytes.Say(fmt.Sprintf(ui.NoOrg, "missingOrg")))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		It("fails for --org without --space", func() {
+			session := runPluginCommand(ts, "autoscaling-policy", fakeAppName, "--org", "otherOrg")
+
+			Expect(session).To(gbytes.Say(ui.OrgWithoutSpace))
+			Expect(session.ExitCode()).To(Equal(1))
+		})
+
+		It("uses the app GUID without looking up the app", func() {
+			setUntargeted(rpcHandlers)
+			apiServer.RouteToHandler("GET", "/v3/apps",
+				ghttp.RespondWith(http.StatusInternalServerError, ""),
+			)
+			session := runPluginCommand(ts, "autoscaling-policy", "my-app", "--app-guid", fakeAppID)
+
+			Expect(session.Out).To(gbytes.Say(ui.ShowPolicyHint, "my-app"))
+			Expect(session.Out).To(gbytes.Say(`"instance_min_count": 1`))
+			Expect(session.ExitCode()).To(Equal(0))
+		})
+	})
+
 })
 
 // convertToNipIoURL converts a local(IP-based) URL to a nip.io URL,
@@ -2739,10 +5449,26 @@ func setUntargeted(rpcHandlers *rpcserverfakes.FakeHandlers) {
 }
 
 func runPluginCommand(server *rpcserver.TestServer, args ...string) *gexec.Session {
+	GinkgoHelper()
+	return runPluginCommandWithStdin(server, nil, args...)
+}
+
+// startPluginCommand starts a long running command without waiting for it
+func startPluginCommand(server *rpcserver.TestServer, args ...string) *gexec.Session {
 	GinkgoHelper()
 	args = append([]string{server.Port()}, args...)
 	session, err := gexec.Start(exec.Command(validPluginPath, args...), GinkgoWriter, GinkgoWriter)
 	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+func runPluginCommandWithStdin(server *rpcserver.TestServer, stdin io.Reader, args ...string) *gexec.Session {
+	GinkgoHelper()
+	args = append([]string{server.Port()}, args...)
+	command := exec.Command(validPluginPath, args...)
+	command.Stdin = stdin
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
 	session.Wait()
 	return session
 }
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type PolicyChangeType string
+
+const (
+	PolicyChangeAdded   PolicyChangeType = "added"
+	PolicyChangeRemoved PolicyChangeType = "removed"
+	PolicyChangeChanged PolicyChangeType = "changed"
+)
+
+// PolicyChange is a semantic difference between two policies. Path points at
+// the item in the policy it was taken from, i.e. the old policy for removed
+// items and the new policy otherwise.
+type PolicyChange struct {
+	Type        PolicyChangeType
+	Path        string
+	Description string
+}
+
+// DiffPolicies compares two policies semantically: the order of rules and
+// schedules does not matter, rules are matched by metric type and operator,
+// schedules that are left over are matched in order so that shifted time
+// windows show up as changes.
+func DiffPolicies(oldPolicy, newPolicy *ScalingPolicy) []PolicyChange {
+	if oldPolicy == nil {
+		oldPolicy = &ScalingPolicy{}
+	}
+	if newPolicy == nil {
+		newPolicy = &ScalingPolicy{}
+	}
+
+	var changes []PolicyChange
+	if oldPolicy.InstanceMin != newPolicy.InstanceMin {
+		changes = append(changes, PolicyChange{PolicyChangeChanged, "instance_min_count", fmt.Sprintf("%d -> %d", oldPolicy.InstanceMin, newPolicy.InstanceMin)})
+	}
+	if oldPolicy.InstanceMax != newPolicy.InstanceMax {
+		changes = append(changes, PolicyChange{PolicyChangeChanged, "instance_max_count", fmt.Sprintf("%d -> %d", oldPolicy.InstanceMax, newPolicy.InstanceMax)})
+	}
+
+	changes = append(changes, diffItems("scaling_rules", toItems(oldPolicy.ScalingRules), toItems(newPolicy.ScalingRules),
+		func(o, n interface{}) bool {
+			oldRule, newRule := o.(*ScalingRule), n.(*ScalingRule)
+			return oldRule.MetricType == newRule.MetricType && oldRule.Operator == newRule.Operator
+		}, false)...)
+
+	oldSchedules, newSchedules := oldPolicy.Schedules, newPolicy.Schedules
+	if oldSchedules == nil {
+		oldSchedules = &ScalingSchedules{}
+	}
+	if newSchedules == nil {
+		newSchedules = &ScalingSchedules{}
+	}
+	if oldSchedules.Timezone != newSchedules.Timezone {
+		changes = append(changes, PolicyChange{PolicyChangeChanged, "schedules.timezone", fmt.Sprintf("%s -> %s", formatValue(oldSchedules.Timezone), formatValue(newSchedules.Timezone))})
+	}
+	changes = append(changes, diffItems("schedules.recurring_schedule", toItems(oldSchedules.RecurringSchedules), toItems(newSchedules.RecurringSchedules),
+		func(o, n interface{}) bool {
+			oldSchedule, newSchedule := o.(*RecurringSchedule), n.(*RecurringSchedule)
+			return reflect.DeepEqual(sortedDays(oldSchedule.DaysOfWeek), sortedDays(newSchedule.DaysOfWeek)) &&
+				reflect.DeepEqual(sortedDays(oldSchedule.DaysOfMonth), sortedDays(newSchedule.DaysOfMonth))
+		}, true)...)
+	changes = append(changes, diffItems("schedules.specific_date", toItems(oldSchedules.SpecificDateSchedules), toItems(newSchedules.SpecificDateSchedules),
+		func(o, n interface{}) bool { return false }, true)...)
+
+	oldAllowFrom, newAllowFrom := "", ""
+	if oldPolicy.Configuration != nil {
+		oldAllowFrom = oldPolicy.Configuration.CustomMetrics.MetricSubmissionStrategy.AllowFrom
+	}
+	if newPolicy.Configuration != nil {
+		newAllowFrom = newPolicy.Configuration.CustomMetrics.MetricSubmissionStrategy.AllowFrom
+	}
+	if oldAllowFrom != newAllowFrom {
+		changes = append(changes, PolicyChange{PolicyChangeChanged, "configuration.custom_metrics.metric_submission_strategy.allow_from",
+			fmt.Sprintf("%s -> %s", formatValue(oldAllowFrom), formatValue(newAllowFrom))})
+	}
+
+	return changes
+}
+
+func toItems(slice interface{}) []interface{} {
+	v := reflect.ValueOf(slice)
+	var items []interface{}
+	for i := 0; i < v.Len(); i++ {
+		if !v.Index(i).IsNil() {
+			items = append(items, v.Index(i).Interface())
+		}
+	}
+	return items
+}
+
+// diffItems pairs identical items first, then items with the same key, and
+// finally, if pairInOrder is set, whatever is left in order of appearance.
+func diffItems(path string, oldItems, newItems []interface{}, sameKey func(o, n interface{}) bool, pairInOrder bool) []PolicyChange {
+	oldPaired := make([]bool, len(oldItems))
+	newPaired := make([]int, len(newItems))
+	for j := range newPaired {
+		newPaired[j] = -1
+	}
+
+	pair := func(match func(o, n interface{}) bool) {
+		for j, n := range newItems {
+			if newPaired[j] >= 0 {
+				continue
+			}
+			for i, o := range oldItems {
+				if !oldPaired[i] && match(o, n) {
+					oldPaired[i] = true
+					newPaired[j] = i
+					break
+				}
+			}
+		}
+	}
+	pair(func(o, n interface{}) bool { return len(fieldChanges(o, n)) == 0 })
+	pair(sameKey)
+	if pairInOrder {
+		pair(func(o, n interface{}) bool { return true })
+	}
+
+	var changes []PolicyChange
+	for i, o := range oldItems {
+		if !oldPaired[i] {
+			changes = append(changes, PolicyChange{PolicyChangeRemoved, fmt.Sprintf("%s[%d]", path, i), summarize(o)})
+		}
+	}
+	for j, n := range newItems {
+		if newPaired[j] < 0 {
+			changes = append(changes, PolicyChange{PolicyChangeAdded, fmt.Sprintf("%s[%d]", path, j), summarize(n)})
+		} else if fields := fieldChanges(oldItems[newPaired[j]], n); len(fields) > 0 {
+			changes = append(changes, PolicyChange{PolicyChangeChanged, fmt.Sprintf("%s[%d]", path, j), strings.Join(fields, ", ")})
+		}
+	}
+	return changes
+}
+
+func fieldChanges(o, n interface{}) []string {
+	oldValue, newValue := reflect.ValueOf(o).Elem(), reflect.ValueOf(n).Elem()
+	var fields []string
+	for i := 0; i < oldValue.NumField(); i++ {
+		oldField, newField := normalize(oldValue.Field(i).Interface()), normalize(newValue.Field(i).Interface())
+		if !reflect.DeepEqual(oldField, newField) {
+			fields = append(fields, fmt.Sprintf("%s %s -> %s", jsonName(oldValue.Type().Field(i)), formatValue(oldField), formatValue(newField)))
+		}
+	}
+	return fields
+}
+
+func summarize(item interface{}) string {
+	value := reflect.ValueOf(item).Elem()
+	var fields []string
+	for i := 0; i < value.NumField(); i++ {
+		field := normalize(value.Field(i).Interface())
+		if !value.Field(i).IsZero() {
+			fields = append(fields, fmt.Sprintf("%s %s", jsonName(value.Type().Field(i)), formatValue(field)))
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+func jsonName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// normalize makes day lists order-insensitive and treats empty as missing
+func normalize(v interface{}) interface{} {
+	if days, ok := v.([]int); ok {
+		return sortedDays(days)
+	}
+	return v
+}
+
+func sortedDays(days []int) []int {
+	if len(days) == 0 {
+		return nil
+	}
+	sorted := append([]int{}, days...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		if value == "" {
+			return "(none)"
+		}
+		return value
+	case []int:
+		if len(value) == 0 {
+			return "(none)"
+		}
+		return strings.Trim(strings.Join(strings.Fields(fmt.Sprint(value)), ","), "[]")
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
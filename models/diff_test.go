@@ -0,0 +1,104 @@
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+)
+
+var _ = Describe("Policy Diff Test", func() {
+
+	var oldPolicy, newPolicy *ScalingPolicy
+
+	newTestPolicy := func() *ScalingPolicy {
+		return &ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 5,
+			ScalingRules: []*ScalingRule{
+				{MetricType: "memoryused", Threshold: 30, Operator: "<=", Adjustment: "-1"},
+				{MetricType: "memoryused", Threshold: 80, Operator: ">", Adjustment: "+1"},
+			},
+			Schedules: &ScalingSchedules{
+				Timezone: "Europe/Berlin",
+				RecurringSchedules: []*RecurringSchedule{
+					{StartTime: "08:00", EndTime: "18:00", DaysOfWeek: []int{1, 2, 3}, ScheduledInstanceMin: 2, ScheduledInstanceMax: 5},
+					{StartTime: "08:00", EndTime: "12:00", DaysOfMonth: []int{1}, ScheduledInstanceMin: 2, ScheduledInstanceMax: 5},
+				},
+				SpecificDateSchedules: []*SpecificDateSchedule{
+					{StartDateTime: "2099-01-01T10:00", EndDateTime: "2099-01-01T12:00", ScheduledInstanceMin: 2, ScheduledInstanceMax: 5},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		oldPolicy = newTestPolicy()
+		newPolicy = newTestPolicy()
+	})
+
+	It("finds no difference in identical policies", func() {
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(BeEmpty())
+	})
+
+	It("ignores the order of rules, schedules and days", func() {
+		newPolicy.ScalingRules[0], newPolicy.ScalingRules[1] = newPolicy.ScalingRules[1], newPolicy.ScalingRules[0]
+		schedules := newPolicy.Schedules.RecurringSchedules
+		schedules[0], schedules[1] = schedules[1], schedules[0]
+		schedules[1].DaysOfWeek = []int{3, 1, 2}
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(BeEmpty())
+	})
+
+	It("reports changed instance counts", func() {
+		newPolicy.InstanceMax = 10
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(ConsistOf(
+			PolicyChange{Type: PolicyChangeChanged, Path: "instance_max_count", Description: "5 -> 10"},
+		))
+	})
+
+	It("reports a changed threshold of the rule with the same metric type and operator", func() {
+		newPolicy.ScalingRules[1].Threshold = 90
+		newPolicy.ScalingRules[1].CoolDownSeconds = 300
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(ConsistOf(
+			PolicyChange{Type: PolicyChangeChanged, Path: "scaling_rules[1]", Description: "threshold 80 -> 90, cool_down_secs 0 -> 300"},
+		))
+	})
+
+	It("reports added and removed rules", func() {
+		newPolicy.ScalingRules[0] = &ScalingRule{MetricType: "cpu", Threshold: 20, Operator: "<", Adjustment: "-1"}
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(ConsistOf(
+			PolicyChange{Type: PolicyChangeRemoved, Path: "scaling_rules[0]", Description: "metric_type memoryused, threshold 30, operator <=, adjustment -1"},
+			PolicyChange{Type: PolicyChangeAdded, Path: "scaling_rules[0]", Description: "metric_type cpu, threshold 20, operator <, adjustment -1"},
+		))
+	})
+
+	It("reports shifted schedules", func() {
+		newPolicy.Schedules.RecurringSchedules[0].StartTime = "09:00"
+		newPolicy.Schedules.SpecificDateSchedules[0].EndDateTime = "2099-01-01T14:00"
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(ConsistOf(
+			PolicyChange{Type: PolicyChangeChanged, Path: "schedules.recurring_schedule[0]", Description: "start_time 08:00 -> 09:00"},
+			PolicyChange{Type: PolicyChangeChanged, Path: "schedules.specific_date[0]", Description: "end_date_time 2099-01-01T12:00 -> 2099-01-01T14:00"},
+		))
+	})
+
+	It("reports removed schedules and a changed time zone", func() {
+		newPolicy.Schedules.Timezone = "UTC"
+		newPolicy.Schedules.SpecificDateSchedules = nil
+
+		Expect(DiffPolicies(oldPolicy, newPolicy)).To(ConsistOf(
+			PolicyChange{Type: PolicyChangeChanged, Path: "schedules.timezone", Description: "Europe/Berlin -> UTC"},
+			PolicyChange{Type: PolicyChangeRemoved, Path: "schedules.specific_date[0]", Description: "start_date_time 2099-01-01T10:00, end_date_time 2099-01-01T12:00, instance_min_count 2, instance_max_count 5"},
+		))
+	})
+
+	It("treats a missing policy as empty", func() {
+		Expect(DiffPolicies(nil, newPolicy)).To(ContainElement(
+			PolicyChange{Type: PolicyChangeAdded, Path: "schedules.recurring_schedule[1]", Description: "start_time 08:00, end_time 12:00, days_of_month 1, instance_min_count 2, instance_max_count 5"},
+		))
+	})
+})
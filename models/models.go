@@ -2,58 +2,68 @@ package models
 
 type ScalingType int
 
+const (
+	ScalingTypeDynamic ScalingType = iota
+	ScalingTypeScheduled
+)
+
 type ScalingStatus int
 
+const (
+	ScalingStatusSucceeded ScalingStatus = iota
+	ScalingStatusFailed
+)
+
 type Configuration struct {
 	CustomMetrics struct {
 		MetricSubmissionStrategy struct {
-			AllowFrom string `json:"allow_from"`
-		} `json:"metric_submission_strategy"`
-	} `json:"custom_metrics"`
+			AllowFrom string `json:"allow_from" yaml:"allow_from"`
+		} `json:"metric_submission_strategy" yaml:"metric_submission_strategy"`
+	} `json:"custom_metrics" yaml:"custom_metrics"`
 }
 
 type ScalingPolicy struct {
-	InstanceMin   int               `json:"instance_min_count"`
-	InstanceMax   int               `json:"instance_max_count"`
-	ScalingRules  []*ScalingRule    `json:"scaling_rules,omitempty"`
-	Schedules     *ScalingSchedules `json:"schedules,omitempty"`
-	Configuration *Configuration    `json:"configuration,omitempty"`
+	InstanceMin   int               `json:"instance_min_count" yaml:"instance_min_count"`
+	InstanceMax   int               `json:"instance_max_count" yaml:"instance_max_count"`
+	ScalingRules  []*ScalingRule    `json:"scaling_rules,omitempty" yaml:"scaling_rules,omitempty"`
+	Schedules     *ScalingSchedules `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	Configuration *Configuration    `json:"configuration,omitempty" yaml:"configuration,omitempty"`
 }
 
 type ScalingRule struct {
-	MetricType            string `json:"metric_type"`
-	StatWindowSeconds     int    `json:"stat_window_secs,omitempty"`
-	BreachDurationSeconds int    `json:"breach_duration_secs,omitempty"`
-	Threshold             int64  `json:"threshold"`
-	Operator              string `json:"operator"`
-	CoolDownSeconds       int    `json:"cool_down_secs,omitempty"`
-	Adjustment            string `json:"adjustment"`
+	MetricType            string `json:"metric_type" yaml:"metric_type"`
+	StatWindowSeconds     int    `json:"stat_window_secs,omitempty" yaml:"stat_window_secs,omitempty"`
+	BreachDurationSeconds int    `json:"breach_duration_secs,omitempty" yaml:"breach_duration_secs,omitempty"`
+	Threshold             int64  `json:"threshold" yaml:"threshold"`
+	Operator              string `json:"operator" yaml:"operator"`
+	CoolDownSeconds       int    `json:"cool_down_secs,omitempty" yaml:"cool_down_secs,omitempty"`
+	Adjustment            string `json:"adjustment" yaml:"adjustment"`
 }
 
 type ScalingSchedules struct {
-	Timezone              string                  `json:"timezone"`
-	RecurringSchedules    []*RecurringSchedule    `json:"recurring_schedule,omitempty"`
-	SpecificDateSchedules []*SpecificDateSchedule `json:"specific_date,omitempty"`
+	Timezone              string                  `json:"timezone" yaml:"timezone"`
+	RecurringSchedules    []*RecurringSchedule    `json:"recurring_schedule,omitempty" yaml:"recurring_schedule,omitempty"`
+	SpecificDateSchedules []*SpecificDateSchedule `json:"specific_date,omitempty" yaml:"specific_date,omitempty"`
 }
 
 type RecurringSchedule struct {
-	StartTime             string `json:"start_time"`
-	EndTime               string `json:"end_time"`
-	DaysOfWeek            []int  `json:"days_of_week,omitempty"`
-	DaysOfMonth           []int  `json:"days_of_month,omitempty"`
-	StartDate             string `json:"start_date,omitempty"`
-	EndDate               string `json:"end_date,omitempty"`
-	ScheduledInstanceMin  int    `json:"instance_min_count"`
-	ScheduledInstanceMax  int    `json:"instance_max_count"`
-	ScheduledInstanceInit int    `json:"initial_min_instance_count"`
+	StartTime             string `json:"start_time" yaml:"start_time"`
+	EndTime               string `json:"end_time" yaml:"end_time"`
+	DaysOfWeek            []int  `json:"days_of_week,omitempty" yaml:"days_of_week,omitempty"`
+	DaysOfMonth           []int  `json:"days_of_month,omitempty" yaml:"days_of_month,omitempty"`
+	StartDate             string `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate               string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+	ScheduledInstanceMin  int    `json:"instance_min_count" yaml:"instance_min_count"`
+	ScheduledInstanceMax  int    `json:"instance_max_count" yaml:"instance_max_count"`
+	ScheduledInstanceInit int    `json:"initial_min_instance_count" yaml:"initial_min_instance_count"`
 }
 
 type SpecificDateSchedule struct {
-	StartDateTime         string `json:"start_date_time"`
-	EndDateTime           string `json:"end_date_time"`
-	ScheduledInstanceMin  int    `json:"instance_min_count"`
-	ScheduledInstanceMax  int    `json:"instance_max_count"`
-	ScheduledInstanceInit int    `json:"initial_min_instance_count"`
+	StartDateTime         string `json:"start_date_time" yaml:"start_date_time"`
+	EndDateTime           string `json:"end_date_time" yaml:"end_date_time"`
+	ScheduledInstanceMin  int    `json:"instance_min_count" yaml:"instance_min_count"`
+	ScheduledInstanceMax  int    `json:"instance_max_count" yaml:"instance_max_count"`
+	ScheduledInstanceInit int    `json:"initial_min_instance_count" yaml:"initial_min_instance_count"`
 }
 
 type AppAggregatedMetric struct {
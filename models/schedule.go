@@ -0,0 +1,166 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// scheduleHorizonDays bounds the search for upcoming windows of a
+	// recurring schedule
+	scheduleHorizonDays = 2 * 366
+
+	// ShortScheduleGap bounds the gaps between two schedule windows that are
+	// marked as short, as such gaps are often unintended
+	ShortScheduleGap = time.Hour
+)
+
+// ScheduleWindow is a concrete time window of a schedule in the time zone of
+// the policy. Schedule points at the schedule in the JSON document, e.g.
+// recurring_schedule[0].
+type ScheduleWindow struct {
+	Schedule     string
+	Start, End   time.Time
+	InstanceMin  int
+	InstanceMax  int
+	InstanceInit int
+	// Overlaps lists the schedules of windows overlapping this one
+	Overlaps []string
+	// Gap is the time to the next window, zero if the next window overlaps
+	// or follows without a gap or is not known
+	Gap time.Duration
+}
+
+// ShortGap reports if the gap to the next window is shorter than
+// ShortScheduleGap
+func (window *ScheduleWindow) ShortGap() bool {
+	return window.Gap > 0 && window.Gap < ShortScheduleGap
+}
+
+// UpcomingScheduleWindows expands the schedules into the next n windows that
+// end after from, ordered by their start. The initial instance count of a
+// window defaults to its instance_min_count.
+func (policy *ScalingPolicy) UpcomingScheduleWindows(from time.Time, n int) ([]*ScheduleWindow, error) {
+	if policy.Schedules == nil {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(policy.Schedules.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid IANA time zone", policy.Schedules.Timezone)
+	}
+
+	var windows []*ScheduleWindow
+	add := func(schedule string, start, end time.Time, min, max, initial int) {
+		if !end.After(from) {
+			return
+		}
+		if initial == 0 {
+			initial = min
+		}
+		windows = append(windows, &ScheduleWindow{Schedule: schedule, Start: start, End: end,
+			InstanceMin: min, InstanceMax: max, InstanceInit: initial})
+	}
+	for i, schedule := range policy.Schedules.SpecificDateSchedules {
+		if start, end, ok := schedule.window(location); ok {
+			add(fmt.Sprintf("specific_date[%d]", i), start, end,
+				schedule.ScheduledInstanceMin, schedule.ScheduledInstanceMax, schedule.ScheduledInstanceInit)
+		}
+	}
+	first := from.In(location)
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location)
+	for i, schedule := range policy.Schedules.RecurringSchedules {
+		if schedule == nil {
+			continue
+		}
+		// the horizon counts from the start date of the schedule to find
+		// schedules starting far in the future, one more window than
+		// requested is needed for its overlaps and gaps
+		day := first
+		if startDate, err := time.ParseInLocation(ScheduleDateLayout, schedule.StartDate, location); err == nil && startDate.After(day) {
+			day = startDate
+		}
+		for d, found := 0, 0; d < scheduleHorizonDays && found <= n; d, day = d+1, day.AddDate(0, 0, 1) {
+			if start, end, ok := schedule.window(day); ok && end.After(from) {
+				add(fmt.Sprintf("recurring_schedule[%d]", i), start, end,
+					schedule.ScheduledInstanceMin, schedule.ScheduledInstanceMax, schedule.ScheduledInstanceInit)
+				found++
+			}
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+
+	if len(windows) > n {
+		// keep the window after the last one to find overlaps and gaps
+		windows = windows[:n+1]
+	}
+	end := time.Time{}
+	for i, window := range windows {
+		for _, other := range windows[i+1:] {
+			if !other.Start.Before(window.End) {
+				break
+			}
+			window.Overlaps = append(window.Overlaps, other.Schedule)
+			other.Overlaps = append(other.Overlaps, window.Schedule)
+		}
+		if window.End.After(end) {
+			end = window.End
+		}
+		if i+1 < len(windows) {
+			if gap := windows[i+1].Start.Sub(end); gap > 0 {
+				window.Gap = gap
+			}
+		}
+	}
+	if len(windows) > n {
+		windows = windows[:n]
+	}
+	return windows, nil
+}
+
+// window returns the start and end of the schedule in the time zone
+func (schedule *SpecificDateSchedule) window(location *time.Location) (time.Time, time.Time, bool) {
+	if schedule == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	start, startErr := time.ParseInLocation(ScheduleDateTimeLayout, schedule.StartDateTime, location)
+	end, endErr := time.ParseInLocation(ScheduleDateTimeLayout, schedule.EndDateTime, location)
+	return start, end, startErr == nil && endErr == nil
+}
+
+// window returns the start and end of the schedule on the day of the given
+// time in its time zone, ok is false if the schedule does not apply that day
+func (schedule *RecurringSchedule) window(day time.Time) (time.Time, time.Time, bool) {
+	if schedule == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	date := day.Format(ScheduleDateLayout)
+	if (schedule.StartDate != "" && date < schedule.StartDate) || (schedule.EndDate != "" && date > schedule.EndDate) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// days of week count from 1 for Monday to 7 for Sunday
+	weekday := int(day.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	if len(schedule.DaysOfWeek) > 0 && !containsDay(schedule.DaysOfWeek, weekday) {
+		return time.Time{}, time.Time{}, false
+	}
+	if len(schedule.DaysOfMonth) > 0 && !containsDay(schedule.DaysOfMonth, day.Day()) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start, startErr := time.ParseInLocation(ScheduleDateTimeLayout, date+"T"+schedule.StartTime, day.Location())
+	end, endErr := time.ParseInLocation(ScheduleDateTimeLayout, date+"T"+schedule.EndTime, day.Location())
+	return start, end, startErr == nil && endErr == nil
+}
+
+func containsDay(days []int, day int) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
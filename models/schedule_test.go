@@ -0,0 +1,93 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+)
+
+var _ = Describe("Schedule Windows Test", func() {
+
+	var (
+		policy *ScalingPolicy
+		from   = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC) // a Monday
+		starts = func(windows []*ScheduleWindow) []string {
+			var result []string
+			for _, window := range windows {
+				result = append(result, window.Schedule+" "+window.Start.Format("2006-01-02 15:04 MST"))
+			}
+			return result
+		}
+	)
+
+	BeforeEach(func() {
+		policy = &ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 4,
+			Schedules: &ScalingSchedules{
+				Timezone: "Europe/Berlin",
+				RecurringSchedules: []*RecurringSchedule{
+					{StartTime: "08:00", EndTime: "18:00", DaysOfWeek: []int{1, 3}, ScheduledInstanceMin: 2, ScheduledInstanceMax: 6},
+				},
+			},
+		}
+	})
+
+	It("expands recurring schedules in the time zone of the policy", func() {
+		windows, err := policy.UpcomingScheduleWindows(from, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(starts(windows)).To(Equal([]string{
+			"recurring_schedule[0] 2026-01-05 08:00 CET",
+			"recurring_schedule[0] 2026-01-07 08:00 CET",
+			"recurring_schedule[0] 2026-01-12 08:00 CET",
+		}))
+		Expect(windows[0].InstanceInit).To(Equal(2))
+	})
+
+	It("honors days of month and the start and end dates", func() {
+		policy.Schedules.RecurringSchedules[0] = &RecurringSchedule{StartDate: "2026-03-01", EndDate: "2026-05-31",
+			StartTime: "00:00", EndTime: "06:00", DaysOfMonth: []int{31}, ScheduledInstanceMin: 2, ScheduledInstanceMax: 6}
+		windows, err := policy.UpcomingScheduleWindows(from, 5)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(starts(windows)).To(Equal([]string{
+			"recurring_schedule[0] 2026-03-31 00:00 CEST",
+			"recurring_schedule[0] 2026-05-31 00:00 CEST",
+		}))
+	})
+
+	It("flags overlaps and gaps, marking the short ones", func() {
+		policy.Schedules.SpecificDateSchedules = []*SpecificDateSchedule{
+			{StartDateTime: "2026-01-05T18:30", EndDateTime: "2026-01-07T09:00", ScheduledInstanceMin: 3, ScheduledInstanceMax: 8, ScheduledInstanceInit: 5},
+		}
+		windows, err := policy.UpcomingScheduleWindows(from, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(starts(windows)).To(Equal([]string{
+			"recurring_schedule[0] 2026-01-05 08:00 CET",
+			"specific_date[0] 2026-01-05 18:30 CET",
+			"recurring_schedule[0] 2026-01-07 08:00 CET",
+		}))
+		Expect(windows[0].Gap).To(Equal(30 * time.Minute))
+		Expect(windows[0].ShortGap()).To(BeTrue())
+		Expect(windows[0].Overlaps).To(BeEmpty())
+		Expect(windows[1].InstanceInit).To(Equal(5))
+		Expect(windows[1].Gap).To(BeZero())
+		Expect(windows[1].Overlaps).To(Equal([]string{"recurring_schedule[0]"}))
+		Expect(windows[2].Gap).To(Equal(110 * time.Hour))
+		Expect(windows[2].ShortGap()).To(BeFalse())
+		Expect(windows[2].Overlaps).To(Equal([]string{"specific_date[0]"}))
+	})
+
+	It("has no windows without schedules", func() {
+		policy.Schedules = nil
+		windows, err := policy.UpcomingScheduleWindows(from, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(windows).To(BeEmpty())
+	})
+})
@@ -0,0 +1,259 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBreachDurationSeconds applies to scaling rules without breach_duration_secs
+	DefaultBreachDurationSeconds = 120
+	// DefaultCoolDownSeconds applies to scaling rules without cool_down_secs
+	DefaultCoolDownSeconds = 300
+)
+
+// SimulatedScaling is a scaling decision the policy would have made, it has
+// the fields of AppScalingHistory that can be derived without scaling.
+type SimulatedScaling struct {
+	Timestamp    int64       `json:"timestamp"`
+	ScalingType  ScalingType `json:"scaling_type"`
+	OldInstances int         `json:"old_instances"`
+	NewInstances int         `json:"new_instances"`
+	Reason       string      `json:"reason"`
+}
+
+// scheduleLimits are the instance limits in effect, key identifies the
+// active schedule and is empty for the default limits of the policy
+type scheduleLimits struct {
+	key      string
+	min, max int
+	initial  int
+}
+
+// Simulate replays the aggregated metrics through the scaling rules and
+// schedules of the policy between startTime and endTime, starting with the
+// given number of instances. A rule fires once all its metrics have breached
+// the threshold for breach_duration_secs, no rule fires within the
+// cool_down_secs of the rule that fired last. Decisions that would not change
+// the number of instances are left out. The metrics may be in any order.
+func (policy *ScalingPolicy) Simulate(metrics []*AppAggregatedMetric, instances int, startTime, endTime int64) ([]*SimulatedScaling, error) {
+
+	location := time.UTC
+	if policy.Schedules != nil {
+		var err error
+		location, err = time.LoadLocation(policy.Schedules.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid IANA time zone", policy.Schedules.Timezone)
+		}
+	}
+
+	values := make([]float64, len(metrics))
+	byTime := map[int64][]int{}
+	var times []int64
+	for i, metric := range metrics {
+		value, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of metric %s", metric.Value, metric.Name)
+		}
+		values[i] = value
+		if _, ok := byTime[metric.Timestamp]; !ok {
+			times = append(times, metric.Timestamp)
+		}
+		byTime[metric.Timestamp] = append(byTime[metric.Timestamp], i)
+	}
+	for _, boundary := range policy.scheduleBoundaries(location, startTime, endTime) {
+		if _, ok := byTime[boundary]; !ok {
+			byTime[boundary] = nil
+			times = append(times, boundary)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	var (
+		decisions     []*SimulatedScaling
+		limits        = policy.limitsAt(location, startTime)
+		breachStarts  = make([]int64, len(policy.ScalingRules))
+		breaching     = make([]bool, len(policy.ScalingRules))
+		coolDownUntil int64
+	)
+	decide := func(t int64, scalingType ScalingType, newInstances int, reason string) {
+		if newInstances == instances {
+			return
+		}
+		decisions = append(decisions, &SimulatedScaling{
+			Timestamp:    t,
+			ScalingType:  scalingType,
+			OldInstances: instances,
+			NewInstances: newInstances,
+			Reason:       reason,
+		})
+		instances = newInstances
+	}
+
+	for _, t := range times {
+		if t < startTime || t > endTime {
+			continue
+		}
+
+		if next := policy.limitsAt(location, t); next.key != limits.key {
+			if next.key != "" {
+				target := max(instances, next.initial, next.min)
+				decide(t, ScalingTypeScheduled, min(target, next.max),
+					fmt.Sprintf("schedule starts with instance limits %d-%d", next.min, next.max))
+			} else {
+				decide(t, ScalingTypeScheduled, min(max(instances, next.min), next.max),
+					fmt.Sprintf("schedule ends, instance limits %d-%d", next.min, next.max))
+			}
+			limits = next
+		}
+
+		if len(byTime[t]) == 0 {
+			continue
+		}
+		for _, i := range byTime[t] {
+			for r, rule := range policy.ScalingRules {
+				if rule == nil || rule.MetricType != metrics[i].Name {
+					continue
+				}
+				if !rule.breached(values[i]) {
+					breaching[r] = false
+					continue
+				}
+				if !breaching[r] {
+					breaching[r] = true
+					breachStarts[r] = t
+				}
+			}
+		}
+
+		if t < coolDownUntil {
+			continue
+		}
+		for r, rule := range policy.ScalingRules {
+			if rule == nil || !breaching[r] || t-breachStarts[r] < seconds(rule.BreachDurationSeconds, DefaultBreachDurationSeconds) {
+				continue
+			}
+			newInstances := min(max(rule.adjust(instances), limits.min), limits.max)
+			if newInstances == instances {
+				continue
+			}
+			unit := ""
+			for _, i := range byTime[t] {
+				if metrics[i].Name == rule.MetricType {
+					unit = metrics[i].Unit
+				}
+			}
+			decide(t, ScalingTypeDynamic, newInstances,
+				fmt.Sprintf("%+d instance(s) because %s %s %d%s for %d seconds", newInstances-instances,
+					rule.MetricType, rule.Operator, rule.Threshold, unit,
+					seconds(rule.BreachDurationSeconds, DefaultBreachDurationSeconds)/int64(time.Second)))
+			coolDownUntil = t + seconds(rule.CoolDownSeconds, DefaultCoolDownSeconds)
+			break
+		}
+	}
+	return decisions, nil
+}
+
+// breached compares the value with the threshold of the rule
+func (rule *ScalingRule) breached(value float64) bool {
+	threshold := float64(rule.Threshold)
+	switch rule.Operator {
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	}
+	return false
+}
+
+// adjust applies the adjustment of the rule, a percentage is rounded up to at
+// least one instance
+func (rule *ScalingRule) adjust(instances int) int {
+	adjustment := strings.TrimSuffix(rule.Adjustment, "%")
+	n, err := strconv.Atoi(adjustment)
+	if err != nil {
+		return instances
+	}
+	if adjustment == rule.Adjustment {
+		return instances + n
+	}
+	delta := int(math.Ceil(math.Abs(float64(instances*n)) / 100))
+	if n < 0 {
+		delta = -delta
+	}
+	return instances + delta
+}
+
+// seconds converts optional seconds of a rule to nanoseconds
+func seconds(value int, defaultValue int) int64 {
+	if value == 0 {
+		value = defaultValue
+	}
+	return int64(value) * int64(time.Second)
+}
+
+// limitsAt returns the instance limits at the given time, specific date
+// schedules take precedence over recurring schedules
+func (policy *ScalingPolicy) limitsAt(location *time.Location, t int64) scheduleLimits {
+	limits := scheduleLimits{min: policy.InstanceMin, max: policy.InstanceMax}
+	if policy.Schedules == nil {
+		return limits
+	}
+
+	at := time.Unix(0, t).In(location)
+	for i, schedule := range policy.Schedules.SpecificDateSchedules {
+		start, end, ok := schedule.window(location)
+		if ok && !at.Before(start) && at.Before(end) {
+			return scheduleLimits{key: fmt.Sprintf("specific_date[%d]", i),
+				min: schedule.ScheduledInstanceMin, max: schedule.ScheduledInstanceMax, initial: schedule.ScheduledInstanceInit}
+		}
+	}
+	for i, schedule := range policy.Schedules.RecurringSchedules {
+		start, end, ok := schedule.window(at)
+		if ok && !at.Before(start) && at.Before(end) {
+			return scheduleLimits{key: fmt.Sprintf("recurring_schedule[%d]@%s", i, start.Format(ScheduleDateLayout)),
+				min: schedule.ScheduledInstanceMin, max: schedule.ScheduledInstanceMax, initial: schedule.ScheduledInstanceInit}
+		}
+	}
+	return limits
+}
+
+// scheduleBoundaries lists the starts and ends of the schedules between
+// startTime and endTime
+func (policy *ScalingPolicy) scheduleBoundaries(location *time.Location, startTime, endTime int64) []int64 {
+	if policy.Schedules == nil {
+		return nil
+	}
+
+	var boundaries []int64
+	add := func(ts ...time.Time) {
+		for _, t := range ts {
+			if t.UnixNano() > startTime && t.UnixNano() <= endTime {
+				boundaries = append(boundaries, t.UnixNano())
+			}
+		}
+	}
+	for _, schedule := range policy.Schedules.SpecificDateSchedules {
+		if start, end, ok := schedule.window(location); ok {
+			add(start, end)
+		}
+	}
+	first := time.Unix(0, startTime).In(location)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location)
+	for ; day.UnixNano() <= endTime; day = day.AddDate(0, 0, 1) {
+		for _, schedule := range policy.Schedules.RecurringSchedules {
+			if start, end, ok := schedule.window(day); ok {
+				add(start, end)
+			}
+		}
+	}
+	return boundaries
+}
@@ -0,0 +1,97 @@
+package models_test
+
+import (
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+)
+
+var _ = Describe("Policy Simulation Test", func() {
+
+	var (
+		policy    *ScalingPolicy
+		startTime = time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC) // a Monday
+		at        = func(minutes int) int64 {
+			return startTime.Add(time.Duration(minutes) * time.Minute).UnixNano()
+		}
+		// series returns a memoryused metric per minute from the given minute on
+		series = func(from int, values ...int) []*AppAggregatedMetric {
+			var metrics []*AppAggregatedMetric
+			for i, value := range values {
+				metrics = append(metrics, &AppAggregatedMetric{Name: "memoryused", Unit: "MB", Value: strconv.Itoa(value), Timestamp: at(from + i)})
+			}
+			return metrics
+		}
+		changes = func(decisions []*SimulatedScaling) []string {
+			var result []string
+			for _, decision := range decisions {
+				result = append(result, time.Unix(0, decision.Timestamp).UTC().Format("15:04")+" "+
+					strconv.Itoa(decision.OldInstances)+"->"+strconv.Itoa(decision.NewInstances))
+			}
+			return result
+		}
+	)
+
+	BeforeEach(func() {
+		policy = &ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 4,
+			ScalingRules: []*ScalingRule{
+				{MetricType: "memoryused", BreachDurationSeconds: 120, CoolDownSeconds: 300, Threshold: 80, Operator: ">=", Adjustment: "+1"},
+				{MetricType: "memoryused", BreachDurationSeconds: 120, CoolDownSeconds: 300, Threshold: 30, Operator: "<", Adjustment: "-50%"},
+			},
+		}
+	})
+
+	It("scales once the threshold is breached for the breach duration", func() {
+		decisions, err := policy.Simulate(series(0, 50, 90, 90, 50, 90, 90, 90), 2, at(0), at(10))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes(decisions)).To(Equal([]string{"08:06 2->3"}))
+		Expect(decisions[0].ScalingType).To(Equal(ScalingTypeDynamic))
+		Expect(decisions[0].Reason).To(Equal("+1 instance(s) because memoryused >= 80MB for 120 seconds"))
+	})
+
+	It("honors the cool-down and the instance limits", func() {
+		values := make([]int, 20)
+		for i := range values {
+			values[i] = 90
+		}
+		decisions, err := policy.Simulate(series(0, values...), 2, at(0), at(30))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes(decisions)).To(Equal([]string{"08:02 2->3", "08:07 3->4"}))
+	})
+
+	It("rounds percentage adjustments up", func() {
+		decisions, err := policy.Simulate(series(0, 10, 10, 10), 3, at(0), at(10))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes(decisions)).To(Equal([]string{"08:02 3->1"}))
+	})
+
+	It("applies the instance limits of the schedules in their time zone", func() {
+		policy.Schedules = &ScalingSchedules{
+			Timezone: "Europe/Berlin",
+			RecurringSchedules: []*RecurringSchedule{
+				{StartTime: "09:10", EndTime: "09:40", DaysOfWeek: []int{1}, ScheduledInstanceMin: 5, ScheduledInstanceMax: 6},
+				{StartTime: "08:00", EndTime: "12:00", DaysOfWeek: []int{2}, ScheduledInstanceMin: 2, ScheduledInstanceMax: 6},
+			},
+		}
+		decisions, err := policy.Simulate(nil, 1, at(0), at(60))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes(decisions)).To(Equal([]string{"08:10 1->5", "08:40 5->4"}))
+		Expect(decisions[0].ScalingType).To(Equal(ScalingTypeScheduled))
+	})
+
+	It("fails with an invalid metric value", func() {
+		_, err := policy.Simulate([]*AppAggregatedMetric{{Name: "memoryused", Value: "n/a", Timestamp: at(0)}}, 1, at(0), at(10))
+
+		Expect(err).To(MatchError(`invalid value "n/a" of metric memoryused`))
+	})
+})
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ScheduleTimeLayout     = "15:04"
+	ScheduleDateLayout     = "2006-01-02"
+	ScheduleDateTimeLayout = "2006-01-02T15:04"
+)
+
+var (
+	metricTypePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	adjustmentPattern = regexp.MustCompile(`^[-+][1-9][0-9]*%?$`)
+
+	validOperators = []string{"<", ">", "<=", ">="}
+
+	// built-in metrics whose threshold is a percentage
+	percentageMetrics = map[string]bool{"memoryutil": true, "cpu": true, "cpuutil": true, "diskutil": true}
+	// built-in metrics whose threshold must be positive
+	positiveMetrics = map[string]bool{"memoryused": true, "responsetime": true, "throughput": true, "disk": true}
+)
+
+// ValidationError describes a semantic error in a scaling policy, Path points
+// at the offending field in the JSON document, e.g. scaling_rules[0].operator.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type policyValidator struct {
+	errors ValidationErrors
+}
+
+func (v *policyValidator) add(path string, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the policy with the same rules the AutoScaler API applies,
+// it returns nil if the policy is valid.
+func (policy *ScalingPolicy) Validate() ValidationErrors {
+	v := &policyValidator{}
+
+	if policy.InstanceMin < 1 {
+		v.add("instance_min_count", "must be greater than or equal to 1")
+	}
+	if policy.InstanceMax < 1 {
+		v.add("instance_max_count", "must be greater than or equal to 1")
+	}
+	if policy.InstanceMin > policy.InstanceMax {
+		v.add("instance_min_count", "instance_min_count %d is higher than instance_max_count %d", policy.InstanceMin, policy.InstanceMax)
+	}
+
+	if len(policy.ScalingRules) == 0 && policy.Schedules == nil {
+		v.add("(root)", "at least one of scaling_rules or schedules is required")
+	}
+
+	for i, rule := range policy.ScalingRules {
+		v.validateRule(fmt.Sprintf("scaling_rules[%d]", i), rule)
+	}
+
+	if policy.Schedules != nil {
+		v.validateSchedules("schedules", policy.Schedules)
+	}
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+func (v *policyValidator) validateRule(path string, rule *ScalingRule) {
+	if rule == nil {
+		v.add(path, "must be an object")
+		return
+	}
+
+	if !metricTypePattern.MatchString(rule.MetricType) {
+		v.add(path+".metric_type", "%q is not a valid metric type, use memoryused, memoryutil, responsetime, throughput, cpu, cpuutil, disk, diskutil or a custom metric name built with letters, numbers or underlines", rule.MetricType)
+	}
+
+	if percentageMetrics[rule.MetricType] && (rule.Threshold < 1 || rule.Threshold > 100) {
+		v.add(path+".threshold", "must be between 1 and 100 for metric type %s", rule.MetricType)
+	}
+	if positiveMetrics[rule.MetricType] && rule.Threshold < 1 {
+		v.add(path+".threshold", "must be greater than 0 for metric type %s", rule.MetricType)
+	}
+
+	if !contains(validOperators, rule.Operator) {
+		v.add(path+".operator", "%q must be one of %s", rule.Operator, strings.Join(validOperators, ", "))
+	}
+
+	if !adjustmentPattern.MatchString(rule.Adjustment) {
+		v.add(path+".adjustment", "%q must be a signed number of instances or percentage, e.g. +1, -2, +50%% or -50%%", rule.Adjustment)
+	}
+
+	v.validateSeconds(path+".stat_window_secs", rule.StatWindowSeconds)
+	v.validateSeconds(path+".breach_duration_secs", rule.BreachDurationSeconds)
+	v.validateSeconds(path+".cool_down_secs", rule.CoolDownSeconds)
+}
+
+// validateSeconds accepts 0 as the field is optional and omitted when empty
+func (v *policyValidator) validateSeconds(path string, seconds int) {
+	if seconds != 0 && (seconds < 60 || seconds > 3600) {
+		v.add(path, "%d must be between 60 and 3600", seconds)
+	}
+}
+
+func (v *policyValidator) validateSchedules(path string, schedules *ScalingSchedules) {
+	if schedules.Timezone == "" {
+		v.add(path+".timezone", "is required")
+	} else if _, err := time.LoadLocation(schedules.Timezone); err != nil {
+		v.add(path+".timezone", "%q is not a valid IANA time zone", schedules.Timezone)
+	}
+
+	if len(schedules.RecurringSchedules) == 0 && len(schedules.SpecificDateSchedules) == 0 {
+		v.add(path, "at least one of recurring_schedule or specific_date is required")
+	}
+
+	for i, schedule := range schedules.RecurringSchedules {
+		v.validateRecurringSchedule(fmt.Sprintf("%s.recurring_schedule[%d]", path, i), schedule)
+	}
+
+	type window struct {
+		index      int
+		start, end time.Time
+	}
+	var windows []window
+	for i, schedule := range schedules.SpecificDateSchedules {
+		itemPath := fmt.Sprintf("%s.specific_date[%d]", path, i)
+		start, end, ok := v.validateSpecificDateSchedule(itemPath, schedule)
+		if ok {
+			windows = append(windows, window{index: i, start: start, end: end})
+		}
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].start.Before(windows[j].start) })
+	// compare with the window ending last so far, it may not be the previous one
+	latest := 0
+	for i := 1; i < len(windows); i++ {
+		if windows[i].start.Before(windows[latest].end) {
+			v.add(fmt.Sprintf("%s.specific_date[%d]", path, windows[i].index), "overlaps with %s.specific_date[%d]", path, windows[latest].index)
+		}
+		if windows[i].end.After(windows[latest].end) {
+			latest = i
+		}
+	}
+}
+
+func (v *policyValidator) validateRecurringSchedule(path string, schedule *RecurringSchedule) {
+	if schedule == nil {
+		v.add(path, "must be an object")
+		return
+	}
+
+	start, startErr := time.Parse(ScheduleTimeLayout, schedule.StartTime)
+	if startErr != nil {
+		v.add(path+".start_time", "%q does not match the format HH:mm", schedule.StartTime)
+	}
+	end, endErr := time.Parse(ScheduleTimeLayout, schedule.EndTime)
+	if endErr != nil {
+		v.add(path+".end_time", "%q does not match the format HH:mm", schedule.EndTime)
+	}
+	if startErr == nil && endErr == nil && !end.After(start) {
+		v.add(path+".end_time", "%s must be after start_time %s", schedule.EndTime, schedule.StartTime)
+	}
+
+	switch {
+	case len(schedule.DaysOfWeek) > 0 && len(schedule.DaysOfMonth) > 0:
+		v.add(path, "only one of days_of_week or days_of_month can be specified")
+	case len(schedule.DaysOfWeek) == 0 && len(schedule.DaysOfMonth) == 0:
+		v.add(path, "one of days_of_week or days_of_month is required")
+	}
+	v.validateDays(path+".days_of_week", schedule.DaysOfWeek, 7)
+	v.validateDays(path+".days_of_month", schedule.DaysOfMonth, 31)
+
+	var startDate, endDate time.Time
+	var startDateErr, endDateErr error
+	if schedule.StartDate != "" {
+		startDate, startDateErr = time.Parse(ScheduleDateLayout, schedule.StartDate)
+		if startDateErr != nil {
+			v.add(path+".start_date", "%q does not match the format yyyy-MM-dd", schedule.StartDate)
+		}
+	}
+	if schedule.EndDate != "" {
+		endDate, endDateErr = time.Parse(ScheduleDateLayout, schedule.EndDate)
+		if endDateErr != nil {
+			v.add(path+".end_date", "%q does not match the format yyyy-MM-dd", schedule.EndDate)
+		}
+	}
+	if schedule.StartDate != "" && schedule.EndDate != "" && startDateErr == nil && endDateErr == nil && endDate.Before(startDate) {
+		v.add(path+".end_date", "%s must not be before start_date %s", schedule.EndDate, schedule.StartDate)
+	}
+
+	v.validateInstanceCounts(path, schedule.ScheduledInstanceMin, schedule.ScheduledInstanceMax, schedule.ScheduledInstanceInit)
+}
+
+func (v *policyValidator) validateSpecificDateSchedule(path string, schedule *SpecificDateSchedule) (time.Time, time.Time, bool) {
+	if schedule == nil {
+		v.add(path, "must be an object")
+		return time.Time{}, time.Time{}, false
+	}
+
+	start, startErr := time.Parse(ScheduleDateTimeLayout, schedule.StartDateTime)
+	if startErr != nil {
+		v.add(path+".start_date_time", "%q does not match the format yyyy-MM-ddTHH:mm", schedule.StartDateTime)
+	}
+	end, endErr := time.Parse(ScheduleDateTimeLayout, schedule.EndDateTime)
+	if endErr != nil {
+		v.add(path+".end_date_time", "%q does not match the format yyyy-MM-ddTHH:mm", schedule.EndDateTime)
+	}
+	ok := startErr == nil && endErr == nil
+	if ok && !end.After(start) {
+		v.add(path+".end_date_time", "%s must be after start_date_time %s", schedule.EndDateTime, schedule.StartDateTime)
+		ok = false
+	}
+
+	v.validateInstanceCounts(path, schedule.ScheduledInstanceMin, schedule.ScheduledInstanceMax, schedule.ScheduledInstanceInit)
+	return start, end, ok
+}
+
+func (v *policyValidator) validateDays(path string, days []int, max int) {
+	seen := map[int]bool{}
+	for i, day := range days {
+		if day < 1 || day > max {
+			v.add(fmt.Sprintf("%s[%d]", path, i), "%d must be between 1 and %d", day, max)
+		}
+		if seen[day] {
+			v.add(fmt.Sprintf("%s[%d]", path, i), "%d is duplicated", day)
+		}
+		seen[day] = true
+	}
+}
+
+func (v *policyValidator) validateInstanceCounts(path string, min, max, initial int) {
+	if min < 1 {
+		v.add(path+".instance_min_count", "must be greater than or equal to 1")
+	}
+	if max < 1 {
+		v.add(path+".instance_max_count", "must be greater than or equal to 1")
+	}
+	if min > max {
+		v.add(path+".instance_min_count", "instance_min_count %d is higher than instance_max_count %d", min, max)
+	}
+	if initial != 0 && (initial < min || initial > max) {
+		v.add(path+".initial_min_instance_count", "%d must be between instance_min_count %d and instance_max_count %d", initial, min, max)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
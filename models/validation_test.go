@@ -0,0 +1,161 @@
+package models_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/models"
+)
+
+var _ = Describe("Policy Validation Test", func() {
+
+	var policy *ScalingPolicy
+
+	messagesOf := func(errs ValidationErrors) []string {
+		var messages []string
+		for _, e := range errs {
+			messages = append(messages, e.Error())
+		}
+		return messages
+	}
+
+	BeforeEach(func() {
+		policy = &ScalingPolicy{
+			InstanceMin: 1,
+			InstanceMax: 5,
+			ScalingRules: []*ScalingRule{
+				{
+					MetricType:            "memoryused",
+					StatWindowSeconds:     300,
+					BreachDurationSeconds: 600,
+					Threshold:             30,
+					Operator:              "<=",
+					CoolDownSeconds:       300,
+					Adjustment:            "-1",
+				},
+				{
+					MetricType: "custom_metric_1",
+					Threshold:  0,
+					Operator:   ">",
+					Adjustment: "+50%",
+				},
+			},
+			Schedules: &ScalingSchedules{
+				Timezone: "Europe/Berlin",
+				RecurringSchedules: []*RecurringSchedule{
+					{
+						StartTime:             "08:00",
+						EndTime:               "18:00",
+						DaysOfMonth:           []int{1, 15, 31},
+						StartDate:             "2099-01-01",
+						EndDate:               "2099-12-31",
+						ScheduledInstanceMin:  2,
+						ScheduledInstanceMax:  10,
+						ScheduledInstanceInit: 5,
+					},
+				},
+				SpecificDateSchedules: []*SpecificDateSchedule{
+					{
+						StartDateTime:        "2099-01-01T10:00",
+						EndDateTime:          "2099-01-01T12:00",
+						ScheduledInstanceMin: 2,
+						ScheduledInstanceMax: 10,
+					},
+					{
+						StartDateTime:        "2099-01-01T12:00",
+						EndDateTime:          "2099-01-01T14:00",
+						ScheduledInstanceMin: 2,
+						ScheduledInstanceMax: 10,
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid policy", func() {
+		Expect(policy.Validate()).To(BeNil())
+	})
+
+	It("requires scaling rules or schedules", func() {
+		policy.ScalingRules = nil
+		policy.Schedules = nil
+		Expect(messagesOf(policy.Validate())).To(ConsistOf("(root): at least one of scaling_rules or schedules is required"))
+	})
+
+	It("checks the instance counts", func() {
+		policy.InstanceMin = 0
+		policy.InstanceMax = -1
+		Expect(messagesOf(policy.Validate())).To(ConsistOf(
+			"instance_min_count: must be greater than or equal to 1",
+			"instance_max_count: must be greater than or equal to 1",
+			"instance_min_count: instance_min_count 0 is higher than instance_max_count -1",
+		))
+	})
+
+	It("checks the scaling rules", func() {
+		policy.ScalingRules[0].MetricType = "memory-used"
+		policy.ScalingRules[0].Operator = "=="
+		policy.ScalingRules[0].Adjustment = "+0"
+		policy.ScalingRules[0].CoolDownSeconds = 30
+		policy.ScalingRules[1].MetricType = "cpuutil"
+		policy.ScalingRules[1].Adjustment = "50%"
+		Expect(messagesOf(policy.Validate())).To(ConsistOf(
+			ContainSubstring(`scaling_rules[0].metric_type: "memory-used" is not a valid metric type`),
+			`scaling_rules[0].operator: "==" must be one of <, >, <=, >=`,
+			ContainSubstring(`scaling_rules[0].adjustment: "+0" must be a signed number`),
+			"scaling_rules[0].cool_down_secs: 30 must be between 60 and 3600",
+			"scaling_rules[1].threshold: must be between 1 and 100 for metric type cpuutil",
+			ContainSubstring(`scaling_rules[1].adjustment: "50%" must be a signed number`),
+		))
+	})
+
+	It("checks the recurring schedules", func() {
+		schedule := policy.Schedules.RecurringSchedules[0]
+		schedule.StartTime = "8:00pm"
+		schedule.EndTime = "24:00"
+		schedule.DaysOfWeek = []int{1, 1}
+		schedule.DaysOfMonth = []int{0}
+		schedule.StartDate = "2099-12-31"
+		schedule.EndDate = "2099-01-01"
+		schedule.ScheduledInstanceInit = 20
+		Expect(messagesOf(policy.Validate())).To(ConsistOf(
+			`schedules.recurring_schedule[0].start_time: "8:00pm" does not match the format HH:mm`,
+			`schedules.recurring_schedule[0].end_time: "24:00" does not match the format HH:mm`,
+			"schedules.recurring_schedule[0]: only one of days_of_week or days_of_month can be specified",
+			"schedules.recurring_schedule[0].days_of_week[1]: 1 is duplicated",
+			"schedules.recurring_schedule[0].days_of_month[0]: 0 must be between 1 and 31",
+			"schedules.recurring_schedule[0].end_date: 2099-01-01 must not be before start_date 2099-12-31",
+			"schedules.recurring_schedule[0].initial_min_instance_count: 20 must be between instance_min_count 2 and instance_max_count 10",
+		))
+	})
+
+	It("checks the time zone and the specific date schedules", func() {
+		policy.Schedules.Timezone = "Europe/Atlantis"
+		policy.Schedules.SpecificDateSchedules[0].EndDateTime = "2099-01-01T13:00"
+		policy.Schedules.SpecificDateSchedules = append(policy.Schedules.SpecificDateSchedules, &SpecificDateSchedule{
+			StartDateTime:        "2099-01-02 10:00",
+			EndDateTime:          "2099-01-02T09:00",
+			ScheduledInstanceMin: 2,
+			ScheduledInstanceMax: 1,
+		})
+		Expect(messagesOf(policy.Validate())).To(ConsistOf(
+			`schedules.timezone: "Europe/Atlantis" is not a valid IANA time zone`,
+			"schedules.specific_date[1]: overlaps with schedules.specific_date[0]",
+			`schedules.specific_date[2].start_date_time: "2099-01-02 10:00" does not match the format yyyy-MM-ddTHH:mm`,
+			"schedules.specific_date[2].instance_min_count: instance_min_count 2 is higher than instance_max_count 1",
+		))
+	})
+
+	It("finds specific date schedules overlapping an earlier, longer one", func() {
+		policy.Schedules.SpecificDateSchedules = []*SpecificDateSchedule{
+			{StartDateTime: "2099-01-01T01:00", EndDateTime: "2099-01-01T10:00", ScheduledInstanceMin: 1, ScheduledInstanceMax: 2},
+			{StartDateTime: "2099-01-01T02:00", EndDateTime: "2099-01-01T03:00", ScheduledInstanceMin: 1, ScheduledInstanceMax: 2},
+			{StartDateTime: "2099-01-01T04:00", EndDateTime: "2099-01-01T05:00", ScheduledInstanceMin: 1, ScheduledInstanceMax: 2},
+			{StartDateTime: "2099-01-01T10:00", EndDateTime: "2099-01-01T11:00", ScheduledInstanceMin: 1, ScheduledInstanceMax: 2},
+		}
+		Expect(messagesOf(policy.Validate())).To(ConsistOf(
+			"schedules.specific_date[1]: overlaps with schedules.specific_date[0]",
+			"schedules.specific_date[2]: overlaps with schedules.specific_date[0]",
+		))
+	})
+})
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	ChartLine      = "line"
+	ChartSparkline = "sparkline"
+
+	defaultTerminalWidth = 80
+	chartHeight          = 12
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+type Chart interface {
+	Add(value float64)
+	AddThreshold(value float64, label string)
+	Print()
+}
+
+// PrintableChart renders a series of values as a line chart or a sparkline.
+// The values are spread over the width, when there are more values than
+// columns each column shows the highest of its values so that short peaks
+// stay visible. Thresholds are drawn as horizontal lines on the line chart
+// and listed below both styles.
+type PrintableChart struct {
+	writer     io.Writer
+	style      string
+	width      int
+	unit       string
+	from, to   string
+	values     []float64
+	thresholds []chartThreshold
+}
+
+type chartThreshold struct {
+	value float64
+	label string
+}
+
+// NewChart creates a chart of the given width in characters, from and to
+// label the start and end of the x axis.
+func NewChart(w io.Writer, style string, width int, unit, from, to string) Chart {
+	return &PrintableChart{
+		writer: w,
+		style:  style,
+		width:  width,
+		unit:   unit,
+		from:   from,
+		to:     to,
+	}
+}
+
+// TerminalWidth returns the width of the terminal of stdout, COLUMNS or 80
+func TerminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return defaultTerminalWidth
+}
+
+func (c *PrintableChart) Add(value float64) {
+	c.values = append(c.values, value)
+}
+
+func (c *PrintableChart) AddThreshold(value float64, label string) {
+	c.thresholds = append(c.thresholds, chartThreshold{value: value, label: label})
+}
+
+func (c *PrintableChart) Print() {
+	if len(c.values) == 0 {
+		return
+	}
+
+	low, high := c.values[0], c.values[0]
+	for _, v := range c.values {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	valueLow, valueHigh := low, high
+	if c.style != ChartSparkline {
+		for _, threshold := range c.thresholds {
+			low, high = math.Min(low, threshold.value), math.Max(high, threshold.value)
+		}
+	}
+	if low == high {
+		low, high = low-1, high+1
+	}
+
+	if c.style == ChartSparkline {
+		c.printSparkline(low, high)
+		fmt.Fprintf(c.writer, "min %s, max %s, %s - %s\n", c.format(valueLow), c.format(valueHigh), c.from, c.to)
+	} else {
+		c.printLines(low, high)
+	}
+	for _, threshold := range c.thresholds {
+		fmt.Fprintf(c.writer, "--- %s\n", threshold.label)
+	}
+}
+
+func (c *PrintableChart) printSparkline(low, high float64) {
+	var line strings.Builder
+	for _, v := range c.columns(c.width) {
+		line.WriteRune(sparks[scale(v, low, high, len(sparks))])
+	}
+	fmt.Fprintln(c.writer, line.String())
+}
+
+func (c *PrintableChart) printLines(low, high float64) {
+	labels := make([]string, chartHeight)
+	labelWidth := 0
+	for row := range labels {
+		labels[row] = c.format(low + (high-low)*float64(row)/float64(chartHeight-1))
+		labelWidth = max(labelWidth, len(labels[row]))
+	}
+
+	columns := c.columns(c.width - labelWidth - 2)
+	grid := make([][]rune, chartHeight)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", len(columns)))
+	}
+	for _, threshold := range c.thresholds {
+		row := scale(threshold.value, low, high, chartHeight)
+		for col := range grid[row] {
+			grid[row][col] = '-'
+		}
+	}
+	for col, v := range columns {
+		grid[scale(v, low, high, chartHeight)][col] = '*'
+	}
+
+	for row := chartHeight - 1; row >= 0; row-- {
+		fmt.Fprintf(c.writer, "%*s |%s\n", labelWidth, labels[row], string(grid[row]))
+	}
+	axis := strings.Repeat(" ", labelWidth) + " +" + strings.Repeat("-", len(columns))
+	fmt.Fprintln(c.writer, axis)
+
+	// the end label is right aligned if it fits, otherwise next to the start
+	padding := len(axis) - labelWidth - 2 - len(c.from) - len(c.to)
+	if padding > 0 {
+		fmt.Fprintf(c.writer, "%s  %s%s%s\n", strings.Repeat(" ", labelWidth), c.from, strings.Repeat(" ", padding), c.to)
+	} else {
+		fmt.Fprintf(c.writer, "%s  %s - %s\n", strings.Repeat(" ", labelWidth), c.from, c.to)
+	}
+}
+
+// columns stretches or reduces the values to the width, a reduced column is
+// the highest value of its bucket
+func (c *PrintableChart) columns(width int) []float64 {
+	width = max(width, 1)
+	columns := make([]float64, width)
+	for col := range columns {
+		start := col * len(c.values) / width
+		end := max((col+1)*len(c.values)/width, start+1)
+		columns[col] = c.values[start]
+		for _, v := range c.values[start:end] {
+			columns[col] = math.Max(columns[col], v)
+		}
+	}
+	return columns
+}
+
+func (c *PrintableChart) format(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64) + c.unit
+}
+
+// scale maps the value to one of n levels between low and high
+func scale(v, low, high float64, n int) int {
+	level := int(math.Round((v - low) / (high - low) * float64(n-1)))
+	return min(max(level, 0), n-1)
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	cjson "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/json"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+	FormatYAML  = "yaml"
+)
+
+// Exporter writes typed records in a machine-readable format. The record is
+// used by the JSON formats as is, the row is used by the CSV format.
+type Exporter interface {
+	Add(record interface{}, row []string) error
+	Flush() error
+}
+
+// NewExporter returns an Exporter writing to w. When w is stdout, commands
+// print their hints only if the output goes to a file and their warnings to
+// stderr, so that stdout holds nothing but the records and stays parseable.
+func NewExporter(w io.Writer, format string, headers []string) Exporter {
+	switch format {
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &jsonlExporter{encoder: encoder}
+	case FormatCSV:
+		return &csvExporter{writer: csv.NewWriter(w), headers: headers}
+	default:
+		return &jsonExporter{writer: w, records: []interface{}{}}
+	}
+}
+
+type jsonExporter struct {
+	writer  io.Writer
+	records []interface{}
+}
+
+func (e *jsonExporter) Add(record interface{}, _ []string) error {
+	e.records = append(e.records, record)
+	return nil
+}
+
+func (e *jsonExporter) Flush() error {
+	content, err := cjson.MarshalWithoutHTMLEscape(e.records)
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(content)
+	return err
+}
+
+type jsonlExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonlExporter) Add(record interface{}, _ []string) error {
+	return e.encoder.Encode(record)
+}
+
+func (e *jsonlExporter) Flush() error {
+	return nil
+}
+
+type csvExporter struct {
+	writer        *csv.Writer
+	headers       []string
+	headerWritten bool
+}
+
+func (e *csvExporter) Add(_ interface{}, row []string) error {
+	if !e.headerWritten {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	return e.writer.Write(row)
+}
+
+func (e *csvExporter) Flush() error {
+	if !e.headerWritten {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvExporter) writeHeader() error {
+	e.headerWritten = true
+	return e.writer.Write(e.headers)
+}
@@ -7,13 +7,25 @@ const (
 	NOCFAPIEndpoint    = "No Cloud Foundry api endpoint set. Use 'cf api' to set Cloud Foundry endpoint first."
 	NoEndpoint         = "No AutoScaler api endpoint set. Use 'cf autoscaling-api' to set an endpoint."
 	NoTarget           = "No org and space targeted, use 'cf target -o ORG -s SPACE' to target an org and space"
+	NoOrgTarget        = "No org targeted, use 'cf target -o ORG' or --org to choose the org of --space."
+	OrgWithoutSpace    = "The --org option requires --space."
 	NoApp              = "App '%s' not found."
+	NoOrg              = "Organization '%s' not found."
+	NoSpace            = "Space '%s' not found in organization %s."
 	APIEndpoint        = "Autoscaler api endpoint: %s"
 	SetAPIEndpoint     = "Setting AutoScaler api endpoint to %s..."
 	UnsetAPIEndpoint   = "Unsetting AutoScaler api endpoint."
 	InvalidAPIEndpoint = "Invalid AutoScaler API endpoint : %s"
-	InvalidSSLCerts    = "Issue connecting to %s: %s\nTIP: Use --skip-ssl-validation to continue with an insecure API endpoint."
+	InvalidSSLCerts    = "Issue connecting to %s: %s\nTIP: Use --ca-cert to trust the CA of the API endpoint, or --skip-ssl-validation to continue with an insecure API endpoint."
+	InvalidCACert      = "Failed to load CA certificate %s: %v."
+	CACertWithSkipSSL  = "The --ca-cert option cannot be used with --skip-ssl-validation."
 	InconsistentDomain = "Failed to set AutoScaler domain to %s since it is inconsistent with the domain of CF API %s."
+	ListAPIEndpoints   = "Listing AutoScaler api endpoints, * marks the endpoint of the current CF api..."
+	NoAPIEndpoints     = "No AutoScaler api endpoints set."
+	RemoveAPIEndpoint  = "Removing AutoScaler api endpoint of %s..."
+	NameAPIEndpoint    = "Naming AutoScaler api endpoint of %s as %s..."
+	NoAPIEndpointFor   = "No AutoScaler api endpoint set for profile or CF api %s."
+	ProfileInUse       = "Profile %s is already used for the AutoScaler api endpoint of %s."
 
 	Unauthorized  = "Unauthorized. Failed to access AutoScaler API endpoint %s."
 	LoginRequired = "You must be logged in %s first."
@@ -22,30 +34,114 @@ const (
 	PolicyNotFound       = "No policy defined for app %s."
 	InvalidPolicy        = "Invalid policy definition: %v."
 
+	FailToLoadPolicyIndex = "Failed to read policy index %s, export the policies with 'cf export-autoscaling-policies' first."
+	InvalidPolicyIndex    = "Invalid policy index %s: %v."
+	PolicyFileOutsideDir  = "policy file %s is not in the directory of the index"
+
+	FailToLoadCredentialFile = "Failed to read credential file %s."
+	InvalidCredential        = "Invalid credential definition: %v."
+
 	ShowPolicyHint   = "Retrieving policy for app %s..."
 	AttachPolicyHint = "Attaching policy for app %s..."
+	BulkAttachHint   = "Attaching policy %s to %d apps in space %s..."
 	DetachPolicyHint = "Detaching policy for app %s..."
+	EditPolicyHint   = "Editing policy for app %s..."
+
+	DryRunAttachPolicyHint = "Attaching policy for app %s (dry run)..."
+	DryRunDetachPolicyHint = "Detaching policy for app %s (dry run)..."
+	DryRunBulkAttachHint   = "Attaching policy %s to %d apps in space %s (dry run)..."
+
+	ValidatePolicyHint = "Validating policy file %s..."
+	DiffPolicyHint     = "Comparing policy of app %s with %s..."
 
 	CreateCredentialHint = "Creating custom metric credential for app %s..."
 	DeleteCredentialHint = "Deleting custom metric credential for app %s..."
 
 	ShowAggregatedMetricsHint = "Retrieving aggregated %s metrics for app %s..."
 	ShowHistoryHint           = "Retrieving scaling event history for app %s..."
+	ShowAppsHint              = "Retrieving autoscaling overview of apps in space %s..."
+	ExportPoliciesHint        = "Exporting policies of apps in space %s to %s..."
+	ImportPoliciesHint        = "Importing policies from %s to apps in space %s..."
+	FollowHistoryHint         = "Following scaling event history for app %s, press Ctrl+C to stop..."
+	WatchAggregatedMetricHint = "Watching aggregated %s metrics for app %s, press Ctrl+C to stop..."
+
+	SummarizeAggregatedMetricsHint = "Summarizing aggregated %s metrics for app %s..."
+	ShowReportHint                 = "Building scaling report for app %s from %s to %s..."
+	SimulatePolicyHint             = "Simulating policy %s for app %s from %s to %s..."
+	ShowScheduleHint               = "Retrieving upcoming schedule windows for app %s..."
+	ShowScheduleFileHint           = "Expanding upcoming schedule windows of policy file %s..."
 
 	SavePolicyHint           = "Saving policy for app %s to %s... "
 	SaveCredentialHint       = "Saving new created credential for app %s to %s..."
 	SaveAggregatedMetricHint = "Saving aggregated metrics for app %s to %s... "
 	SaveHistoryHint          = "Saving scaling event history for app %s to %s... "
+	SaveReportHint           = "Saving scaling report for app %s to %s... "
+	SaveSimulationHint       = "Saving policy simulation for app %s to %s... "
 
-	UnrecognizedTimeFormat = "Unrecognized date time format: %s. \nSupported formats are yyyy-MM-ddTHH:mm:ss+/-hhmm, yyyy-MM-ddTHH:mm:ssZ with an input later than 1970-01-01T00:00:00Z."
+	UnrecognizedTimeFormat = "Unrecognized date time format: %s. \nSupported formats are yyyy-MM-ddTHH:mm:ss+/-hh:mm, yyyy-MM-ddTHH:mm:ssZ, yyyy-MM-ddTHH:mm:ss and yyyy-MM-dd in the local time zone, now, a duration before now like -30m, -1h30m or -7d, and Unix epoch seconds or milliseconds."
+	TimeBeforeEpoch        = "Invalid date time %s, it must be later than 1970-01-01T00:00:00Z."
+	InvalidSinceDuration   = "Invalid duration for --since: %s. \nUse a duration greater than 0 like 30m, 1h30m or 7d."
+	SinceWithStartTime     = "The --since option cannot be used with --start."
+	InvalidTimezone        = "Unrecognized time zone: %s. \nUse an IANA time zone like Europe/Berlin, UTC, or policy for the time zone of the app's schedules."
+	InvalidTimeFormat      = "Unrecognized time format: %s. \nUse rfc3339, rfc3339nano, datetime, time, unix or a Go layout like \"2006-01-02 15:04 MST\"."
+	NoPolicyTimezone       = "App %s has no policy with schedules, its time zone cannot be used."
 	UnrecognizedMetricName = "Unrecognized metric name: %s. \nSupported value: memoryused, memoryutil, responsetime, throughput, cpu or custom metric names built with letters, numbers or underlines \"_\"."
 	InvalidTimeRange       = "Invalid time range. The start time %s is greater than the end time %s."
+	InvalidInterval        = "Invalid poll interval %s, it must be greater than 0."
+	FollowWithEndTime      = "The --end option cannot be used with --follow."
+	FollowWithFormat       = "The --follow option only supports the table format."
+	WatchWithEndTime       = "The --end option cannot be used with --watch."
+	WatchWithFormat        = "The --watch option only supports the table format."
+	SummaryWithWatch       = "The --summary option cannot be used with --watch."
+	ChartWithWatch         = "The --chart option cannot be used with --watch or --summary."
+	ChartWithFormat        = "The --chart option only supports the table format."
+	InvalidMetricValue     = "Invalid value %q of metric %s, it is not a number."
+	AppNameRequired        = "the required argument `APP_NAME` was not provided"
+	MetricArgsRequired     = "the required arguments `APP_NAME` and `METRIC_NAME` were not provided"
+	MetricNameRequired     = "the required argument `METRIC_NAME` was not provided, use a metric name, a comma-separated list of metric names or --all-policy-metrics."
+	AllPolicyWithMetric    = "METRIC_NAME cannot be used with --all-policy-metrics."
+	MultipleMetrics        = "The --watch and --chart options support a single metric only."
+	AttachArgsRequired     = "the required arguments `APP_NAME` and `PATH_TO_POLICY_FILE` were not provided"
+	PolicyFileRequired     = "the required argument `PATH_TO_POLICY_FILE` was not provided"
+	AppNameWithBulkAttach  = "APP_NAME and PATH_TO_POLICY_FILE cannot be used with --apps or --label-selector, use --policy for the policy file."
+	PolicyOptionRequired   = "the required option `--policy` was not provided"
+	PolicyWithAppName      = "The --policy option requires --apps or --label-selector, use PATH_TO_POLICY_FILE with APP_NAME."
+	InvalidAppPattern      = "Invalid app name pattern: %s."
+	InvalidLabelSelector   = "Invalid label selector %s: unrecognized requirement %q."
+	DuplicateLabelSelector = "Invalid label selector %s: a label can only be used once."
+	NoAppsMatched          = "No apps matching the patterns or the label selector were found in space %s."
+	InvalidScheduleCount   = "Invalid number of schedule windows %d, it must be greater than 0."
+
+	PolicyIdentical  = "No differences found."
+	PolicyDiffers    = "%d difference(s) found."
+	NoPolicyAttached = "No policy is attached to app %s yet."
+	NoPolicyMetrics  = "The policy of app %s has no scaling rules."
+	ScheduleTimezone = "Schedules in time zone %s, default instance limits %d-%d:"
+	DetachedPolicy   = "The following policy would be detached:"
+
+	EditPolicyInstructions = "Please edit the policy of app %s below. Lines beginning with a '#' will be ignored,\nand an empty file or leaving the editor without changes will abort the edit."
+	EditPolicyCancelled    = "Edit cancelled, no changes were made."
+	ConfirmAttachPolicy    = "Attach the edited policy to app %s? [y/N]: "
+	EditorFailed           = "Failed to run the editor %s: %v. \nSet the EDITOR environment variable to the editor of your choice."
 
 	AggregatedMetricsNotFound = "No aggregated %s metrics were found for app %s."
 	HistoryNotFound           = "No event history were found for app %s."
+	HistoryNotMatched         = "No event history matching the filters was found for app %s."
+	AppsNotFound              = "No apps were found in space %s."
+	NoScalingSimulated        = "No scaling would have happened and no scaling event was found for app %s."
+	SimulationSummary         = "The policy would have scaled out %d and in %d times, app %s actually scaled out %d and in %d times."
+	ExportPoliciesSummary     = "%d policies exported and listed in %s, %d apps without policy, %d failed."
+	ImportPoliciesSummary     = "%d policies imported, %d failed."
+	AppsOverviewFailed        = "Failed to retrieve the autoscaling overview of %d apps."
+	BulkAttachSummary         = "Policy attached to %d apps, %d failed."
+	DryRunBulkAttachSummary   = "The policy would change %d apps, %d apps have it already, %d failed."
+	NoUpcomingSchedules       = "No upcoming schedule windows were found."
 
 	MoreRecordsWarning      = "TIP: More records available. Please re-run the command with --start or --end option to fetch more."
 	DeprecatedDescWarning   = "TIP: The default order is set to descending now. Please remove the DEPRECATED flag '--desc'."
 	CreateCredentialWarning = "TIP: A new credential generated. Please update the credential setting of your application, and use 'cf restart %s' to ensure your env variable changes take effect."
+	FollowHistoryRetry      = "Failed to retrieve scaling event history, retrying in %s: %s"
+	WatchMetricsRetry       = "Failed to retrieve aggregated metrics, retrying in %s: %s"
+	DryRunWarning           = "TIP: This was a dry run, no changes were made. Re-run the command without --dry-run to apply them."
 	DeleteCredentialWarning = "TIP: The credential removed. Please remove the credential setting from your application, and use 'cf restart %s' to ensure your env variable changes take effect."
 )
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 )
@@ -24,3 +25,23 @@ func SayWarningMessage(message string, args ...interface{}) {
 	c := color.New(color.FgYellow).Add(color.Bold)
 	c.Printf(message+"\n", args...)
 }
+
+func SayWarningMessageToStderr(message string, args ...interface{}) {
+	c := color.New(color.FgYellow).Add(color.Bold)
+	c.Fprintf(os.Stderr, message+"\n", args...)
+}
+
+func SayAdded(message string, args ...interface{}) {
+	c := color.New(color.FgGreen)
+	c.Printf("+ "+message+"\n", args...)
+}
+
+func SayRemoved(message string, args ...interface{}) {
+	c := color.New(color.FgRed)
+	c.Printf("- "+message+"\n", args...)
+}
+
+func SayChanged(message string, args ...interface{}) {
+	c := color.New(color.FgYellow)
+	c.Printf("~ "+message+"\n", args...)
+}
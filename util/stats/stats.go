@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes the distribution of a series of values, the standard
+// deviation is the one of the population.
+type Summary struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+	StdDev float64 `json:"stddev"`
+}
+
+// Summarize computes the summary of the values, the zero summary is returned
+// if there are none.
+func Summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return Summary{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		Median: Percentile(sorted, 50),
+		P90:    Percentile(sorted, 90),
+		P95:    Percentile(sorted, 95),
+		P99:    Percentile(sorted, 99),
+		StdDev: math.Sqrt(squares / float64(len(sorted))),
+	}
+}
+
+// Percentile interpolates linearly between the closest ranks of the sorted
+// values, p is between 0 and 100.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
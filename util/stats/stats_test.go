@@ -0,0 +1,48 @@
+package stats_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/stats"
+)
+
+var _ = Describe("Stats Test", func() {
+
+	It("summarizes unsorted values", func() {
+		summary := Summarize([]float64{4, 1, 3, 2, 5, 6, 7, 10, 9, 8})
+
+		Expect(summary.Count).To(Equal(10))
+		Expect(summary.Min).To(Equal(1.0))
+		Expect(summary.Max).To(Equal(10.0))
+		Expect(summary.Mean).To(Equal(5.5))
+		Expect(summary.Median).To(Equal(5.5))
+		Expect(summary.P90).To(BeNumerically("~", 9.1, 1e-9))
+		Expect(summary.P95).To(BeNumerically("~", 9.55, 1e-9))
+		Expect(summary.P99).To(BeNumerically("~", 9.91, 1e-9))
+		Expect(summary.StdDev).To(BeNumerically("~", 2.8722813, 1e-6))
+	})
+
+	It("summarizes a single value", func() {
+		Expect(Summarize([]float64{42})).To(Equal(Summary{
+			Count: 1, Min: 42, Max: 42, Mean: 42, Median: 42, P90: 42, P95: 42, P99: 42, StdDev: 0,
+		}))
+	})
+
+	It("returns the zero summary without values", func() {
+		Expect(Summarize(nil)).To(Equal(Summary{}))
+	})
+
+	It("does not reorder the values", func() {
+		values := []float64{3, 1, 2}
+		Summarize(values)
+		Expect(values).To(Equal([]float64{3, 1, 2}))
+	})
+
+	It("interpolates percentiles between ranks", func() {
+		sorted := []float64{10, 20, 30, 40}
+		Expect(Percentile(sorted, 0)).To(Equal(10.0))
+		Expect(Percentile(sorted, 50)).To(Equal(25.0))
+		Expect(Percentile(sorted, 100)).To(Equal(40.0))
+	})
+})
@@ -0,0 +1,94 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
+)
+
+const (
+	// TimezonePolicy selects the time zone of the schedules of the app's policy
+	TimezonePolicy = "policy"
+
+	// FormatUnix prints seconds since epoch
+	FormatUnix = "unix"
+)
+
+// named layouts of --time-format, any other value is used as a Go layout
+var timeLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    time.DateTime,
+	"time":        time.TimeOnly,
+	FormatUnix:    FormatUnix,
+}
+
+// Formatter formats timestamps in nanoseconds since epoch for display. The
+// zero value formats in the local time zone with RFC3339.
+type Formatter struct {
+	Location *time.Location
+	Layout   string
+}
+
+// NewFormatter resolves a time zone name and a --time-format value. An empty
+// time zone is the local one, an empty format is rfc3339.
+func NewFormatter(timezone, format string) (Formatter, error) {
+	location, err := LoadLocation(timezone)
+	if err != nil {
+		return Formatter{}, err
+	}
+	layout, err := ParseLayout(format)
+	if err != nil {
+		return Formatter{}, err
+	}
+	return Formatter{Location: location, Layout: layout}, nil
+}
+
+// LoadLocation loads an IANA time zone, UTC or, if empty, the local time
+// zone.
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf(ui.InvalidTimezone, timezone)
+	}
+	return location, nil
+}
+
+// ParseLayout resolves a named layout or validates a Go layout, which has to
+// contain at least one element of the reference time.
+func ParseLayout(format string) (string, error) {
+	if format == "" {
+		return time.RFC3339, nil
+	}
+	if layout, ok := timeLayouts[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	// a layout without elements of the reference time formats any two
+	// times the same
+	first := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	second := time.Date(2007, 2, 3, 16, 5, 6, 7e8, time.FixedZone("CET", 3600))
+	if first.Format(format) == second.Format(format) {
+		return "", fmt.Errorf(ui.InvalidTimeFormat, format)
+	}
+	return format, nil
+}
+
+func (f Formatter) Format(ns int64) string {
+	if f.Layout == FormatUnix {
+		return strconv.FormatInt(ns/int64(time.Second), 10)
+	}
+	location, layout := f.Location, f.Layout
+	if location == nil {
+		location = time.Local
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return time.Unix(0, ns).In(location).Format(layout)
+}
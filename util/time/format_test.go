@@ -0,0 +1,45 @@
+package time_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
+)
+
+var _ = Describe("Format Time Test", func() {
+
+	var ns = time.Date(2026, 7, 1, 12, 30, 15, 500, time.UTC).UnixNano()
+
+	It("formats in the local time zone with RFC3339 by default", func() {
+		Expect(Formatter{}.Format(ns)).To(Equal(time.Unix(0, ns).Format(time.RFC3339)))
+	})
+
+	DescribeTable("time zones and formats",
+		func(timezone, format, expected string) {
+			formatter, err := NewFormatter(timezone, format)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(formatter.Format(ns)).To(Equal(expected))
+		},
+		Entry("UTC", "UTC", "", "2026-07-01T12:30:15Z"),
+		Entry("IANA time zone with daylight saving", "Europe/Berlin", "rfc3339", "2026-07-01T14:30:15+02:00"),
+		Entry("nanoseconds", "UTC", "rfc3339nano", "2026-07-01T12:30:15.0000005Z"),
+		Entry("date time", "Asia/Tokyo", "datetime", "2026-07-01 21:30:15"),
+		Entry("time only", "UTC", "TIME", "12:30:15"),
+		Entry("unix seconds ignore the time zone", "Asia/Tokyo", "unix", "1782909015"),
+		Entry("Go layout", "America/New_York", "02 Jan 15:04 MST", "01 Jul 08:30 EDT"),
+		Entry("Go layout without time zone", "UTC", "2006-01-02 15:04", "2026-07-01 12:30"),
+		Entry("Go layout of the time only", "Europe/Berlin", "15:04:05", "14:30:15"),
+		Entry("Go layout with month name", "UTC", "Jan 2 15:04", "Jul 1 12:30"),
+	)
+
+	It("rejects unknown time zones and formats", func() {
+		_, err := NewFormatter("Nowhere/Town", "")
+		Expect(err).To(MatchError(ContainSubstring("Unrecognized time zone: Nowhere/Town")))
+
+		_, err = NewFormatter("UTC", "long")
+		Expect(err).To(MatchError(ContainSubstring("Unrecognized time format: long")))
+	})
+})
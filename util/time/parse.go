@@ -3,29 +3,125 @@ package time
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/app-autoscaler-cli-plugin/ui"
 )
 
+// time formats with an explicit offset
 var timeFormats = []string{
 	"2006-01-02T15:04:05Z",
 	"2006-01-02T15:04:05-07:00",
+	time.RFC3339Nano,
 }
 
+// time formats without an offset, they are interpreted in the local time zone
+var localTimeFormats = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var (
+	epochPattern = regexp.MustCompile(`^[0-9]+$`)
+	daysPattern  = regexp.MustCompile(`^([0-9]+)d(.*)$`)
+
+	errNonPositiveDuration = errors.New("duration must be greater than 0")
+)
+
+// epoch values below this bound are seconds, above it milliseconds, i.e.
+// seconds up to the year 5138 and milliseconds from 1973 on
+const maxEpochSeconds = 1e11
+
+// ParseTimeFormat parses a start or end time relative to the current time,
+// see ParseTime.
 func ParseTimeFormat(input string) (ns int64, e error) {
+	return ParseTime(input, time.Now())
+}
 
-	if input != "" {
-		for _, format := range timeFormats {
-			t, e := time.Parse(format, input)
-			if e == nil {
-				ns = t.UnixNano()
-				if ns >= 0 {
-					return ns, nil
-				}
+// ParseTime parses a start or end time to nanoseconds since epoch. Besides
+// RFC3339 date times it accepts date times and dates without offset in the
+// local time zone, "now", durations before now like -30m or -2d and Unix
+// epoch seconds or milliseconds.
+func ParseTime(input string, now time.Time) (int64, error) {
+	input = strings.TrimSpace(input)
+
+	t, err := parseTime(input, now)
+	if err != nil {
+		return 0, err
+	}
+	if t.UnixNano() < 0 {
+		return 0, fmt.Errorf(ui.TimeBeforeEpoch, input)
+	}
+	return t.UnixNano(), nil
+}
+
+func parseTime(input string, now time.Time) (time.Time, error) {
+	switch {
+	case input == "":
+		return time.Time{}, fmt.Errorf(ui.UnrecognizedTimeFormat, input)
+	case strings.EqualFold(input, "now"):
+		return now, nil
+	case strings.HasPrefix(input, "-"):
+		d, err := ParseDuration(input[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf(ui.UnrecognizedTimeFormat, input)
+		}
+		return now.Add(-d), nil
+	case epochPattern.MatchString(input):
+		epoch, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf(ui.UnrecognizedTimeFormat, input)
+		}
+		if epoch < maxEpochSeconds {
+			return time.Unix(epoch, 0), nil
+		}
+		return time.UnixMilli(epoch), nil
+	}
+
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, input); err == nil {
+			return t, nil
+		}
+	}
+	for _, format := range localTimeFormats {
+		if t, err := time.ParseInLocation(format, input, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(ui.UnrecognizedTimeFormat, input)
+}
+
+// ParseDuration parses a positive duration like 30m, 1h30m or 7d, a day is
+// 24 hours.
+func ParseDuration(input string) (time.Duration, error) {
+	var d time.Duration
+	if match := daysPattern.FindStringSubmatch(input); match != nil {
+		days, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(days) * 24 * time.Hour
+		input = match[2]
+		if input == "" {
+			if d <= 0 {
+				return 0, errNonPositiveDuration
 			}
+			return d, nil
 		}
 	}
-	e = errors.New(fmt.Sprintf(ui.UnrecognizedTimeFormat, input))
-	return
+
+	rest, err := time.ParseDuration(input)
+	if err != nil {
+		return 0, err
+	}
+	if rest < 0 || d+rest <= 0 {
+		return 0, errNonPositiveDuration
+	}
+	return d + rest, nil
 }
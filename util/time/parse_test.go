@@ -0,0 +1,66 @@
+package time_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/app-autoscaler-cli-plugin/util/time"
+)
+
+var _ = Describe("Parse Time Test", func() {
+
+	var now = time.Date(2026, 3, 15, 12, 30, 0, 0, time.UTC)
+
+	DescribeTable("accepted time formats",
+		func(input string, expected time.Time) {
+			ns, err := ParseTime(input, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ns).To(Equal(expected.UnixNano()))
+		},
+		Entry("UTC date time", "2026-01-02T03:04:05Z", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Entry("date time with offset", "2026-01-02T03:04:05+08:00", time.Date(2026, 1, 1, 19, 4, 5, 0, time.UTC)),
+		Entry("date time with fractional seconds", "2026-01-02T03:04:05.5Z", time.Date(2026, 1, 2, 3, 4, 5, 5e8, time.UTC)),
+		Entry("local date time", "2026-01-02T03:04:05", time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)),
+		Entry("local date time without seconds", "2026-01-02 03:04", time.Date(2026, 1, 2, 3, 4, 0, 0, time.Local)),
+		Entry("local date", "2026-01-02", time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local)),
+		Entry("now", "now", now),
+		Entry("minutes before now", "-30m", now.Add(-30*time.Minute)),
+		Entry("hours and minutes before now", "-1h30m", now.Add(-90*time.Minute)),
+		Entry("days before now", "-7d", now.Add(-7*24*time.Hour)),
+		Entry("days and hours before now", "-1d12h", now.Add(-36*time.Hour)),
+		Entry("epoch seconds", "1767225600", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("epoch milliseconds", "1767225600123", time.Date(2026, 1, 1, 0, 0, 0, 123e6, time.UTC)),
+	)
+
+	DescribeTable("rejected time formats",
+		func(input string, message string) {
+			_, err := ParseTime(input, now)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("empty", "", "Unrecognized date time format"),
+		Entry("unix date", "Mon Jan  2 15:04:05 UTC 2006", "Unrecognized date time format: Mon Jan  2 15:04:05 UTC 2006"),
+		Entry("duration without sign", "30m", "Unrecognized date time format: 30m"),
+		Entry("zero duration", "-0s", "Unrecognized date time format: -0s"),
+		Entry("before epoch", "1969-12-31T23:59:59Z", "Invalid date time 1969-12-31T23:59:59Z, it must be later than 1970-01-01T00:00:00Z"),
+	)
+
+	DescribeTable("durations",
+		func(input string, expected time.Duration, valid bool) {
+			d, err := ParseDuration(input)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(expected))
+		},
+		Entry("minutes", "45m", 45*time.Minute, true),
+		Entry("days", "2d", 48*time.Hour, true),
+		Entry("days and minutes", "1d30m", 24*time.Hour+30*time.Minute, true),
+		Entry("zero days", "0d", time.Duration(0), false),
+		Entry("negative", "-1h", time.Duration(0), false),
+		Entry("garbage", "soon", time.Duration(0), false),
+	)
+})